@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+	"github.com/kristenwomack/reading-app/backend/internal/store"
+)
+
+// runCommand runs a command-line subcommand against the database instead of
+// starting the server
+func runCommand(s *store.Store, args []string) error {
+	switch args[0] {
+	case "import-clippings":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: reading-tracker import-clippings <My Clippings.txt>")
+		}
+		return importClippings(s, args[1])
+	case "import":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: reading-tracker import <storygraph|librarything|calibre> <file>")
+		}
+		return importLibrary(s, args[1], args[2])
+	case "backup":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: reading-tracker backup <file.zip>")
+		}
+		return backupDatabase(s, args[1])
+	case "restore":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: reading-tracker restore <file.zip>")
+		}
+		return restoreDatabase(s, args[1])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// importClippings imports Kindle highlights and notes from a clippings file
+func importClippings(s *store.Store, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	clippings, err := books.ParseClippings(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse clippings: %w", err)
+	}
+	result, err := s.ImportClippings(clippings)
+	if err != nil {
+		return fmt.Errorf("failed to import clippings: %w", err)
+	}
+
+	fmt.Printf("Imported %d highlights and notes (%d already present, %d bookmarks skipped)\n",
+		result.Imported, result.Duplicates, result.Bookmarks)
+	if len(result.Unmatched) > 0 {
+		fmt.Printf("No matching book for %d titles:\n", len(result.Unmatched))
+		for _, title := range result.Unmatched {
+			fmt.Printf("  %s\n", title)
+		}
+	}
+	return nil
+}
+
+// importLibrary adds books from another app's export file
+func importLibrary(s *store.Store, source, path string) error {
+	importer, ok := store.Importers[source]
+	if !ok {
+		return fmt.Errorf("unknown import source %q", source)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	result, err := s.ImportBooks(importer, f)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d books from %s (%d already present, %d without title or author skipped)\n",
+		result.Imported, source, result.Duplicates, result.Skipped)
+	return nil
+}
+
+// backupDatabase writes a backup archive of the whole database. The archive
+// is written to a temporary file and renamed, so a failed backup never
+// leaves a truncated file behind.
+func backupDatabase(s *store.Store, path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".backup-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := s.Backup(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	fmt.Printf("Backed up database to %s\n", path)
+	return nil
+}
+
+// restoreDatabase replaces the whole database with a backup archive
+func restoreDatabase(s *store.Store, path string) error {
+	archive, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	result, err := s.Restore(archive)
+	if err != nil {
+		return fmt.Errorf("failed to restore database: %w", err)
+	}
+
+	names := make([]string, 0, len(result.Tables))
+	for name := range result.Tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Printf("Restored backup (schema version %d):\n", result.SchemaVersion)
+	for _, name := range names {
+		fmt.Printf("  %-16s %d rows\n", name, result.Tables[name])
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package auth
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"net/http"
 	"os"
@@ -132,3 +136,29 @@ func GenerateCSRFToken() string {
 	rand.Read(b)
 	return base64.StdEncoding.EncodeToString(b)
 }
+
+// CalendarToken returns the token that unlocks private calendar data. It is
+// CALENDAR_TOKEN when set, otherwise derived from the password so it
+// survives restarts and changes when the password does. It is "" when
+// neither is configured.
+func CalendarToken() string {
+	if token := os.Getenv("CALENDAR_TOKEN"); token != "" {
+		return token
+	}
+	password := os.Getenv("READING_APP_PASSWORD")
+	if password == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, []byte(password))
+	mac.Write([]byte("calendar"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CheckCalendarToken reports whether token unlocks the private calendar
+func CheckCalendarToken(token string) bool {
+	expected := CalendarToken()
+	if expected == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
@@ -268,3 +268,33 @@ func TestGenerateCSRFToken(t *testing.T) {
 		t.Error("Expected different CSRF tokens")
 	}
 }
+
+// TestCalendarToken verifies the derived and configured calendar tokens
+func TestCalendarToken(t *testing.T) {
+	os.Unsetenv("CALENDAR_TOKEN")
+	os.Unsetenv("READING_APP_PASSWORD")
+	if token := CalendarToken(); token != "" {
+		t.Errorf("Expected no token without a password, got %q", token)
+	}
+	if CheckCalendarToken("") {
+		t.Error("Expected an empty token to be rejected")
+	}
+
+	// Derived from the password
+	os.Setenv("READING_APP_PASSWORD", "correctpassword")
+	defer os.Unsetenv("READING_APP_PASSWORD")
+	derived := CalendarToken()
+	if derived == "" || derived == "correctpassword" {
+		t.Errorf("Expected a derived token, got %q", derived)
+	}
+	if !CheckCalendarToken(derived) || CheckCalendarToken("wrong") {
+		t.Error("Expected only the derived token to be accepted")
+	}
+
+	// Configured explicitly
+	os.Setenv("CALENDAR_TOKEN", "secret-calendar")
+	defer os.Unsetenv("CALENDAR_TOKEN")
+	if !CheckCalendarToken("secret-calendar") || CheckCalendarToken(derived) {
+		t.Error("Expected CALENDAR_TOKEN to replace the derived token")
+	}
+}
@@ -0,0 +1,135 @@
+package books
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// AuthorCredit is a named contributor to a book and their role
+type AuthorCredit struct {
+	Name string
+	Role string // "author", "translator", "editor", "illustrator", ...
+}
+
+// creditRole matches a trailing role annotation such as "Ken Liu (Translator)"
+var creditRole = regexp.MustCompile(`^(.*?)\s*\(([^()]+)\)\s*$`)
+
+// ParseAuthorCredits combines the primary author and the comma-separated
+// additional authors into credits. Additional authors may carry a role in
+// parentheses; without one they are credited as authors.
+func ParseAuthorCredits(author, additional string) []AuthorCredit {
+	var credits []AuthorCredit
+	seen := make(map[string]bool)
+
+	add := func(raw string) {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			return
+		}
+		credit := AuthorCredit{Name: raw, Role: "author"}
+		if m := creditRole.FindStringSubmatch(raw); m != nil {
+			credit.Name = strings.TrimSpace(m[1])
+			credit.Role = strings.ToLower(strings.TrimSpace(m[2]))
+		}
+		key := NormalizeAuthor(credit.Name) + "|" + credit.Role
+		if credit.Name == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		credits = append(credits, credit)
+	}
+
+	add(author)
+	for _, name := range strings.Split(additional, ",") {
+		add(name)
+	}
+	return credits
+}
+
+// NormalizeAuthor returns a key for an author name so that "Octavia E.
+// Butler", "octavia e butler" and "Octavia E Butler" compare equal. Case
+// and punctuation are ignored; middle initials are kept, so use SameAuthor
+// or AuthorKeys to match names that leave them out.
+func NormalizeAuthor(name string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		if r == '-' || r == '\'' || r == '’' {
+			return -1
+		}
+		return ' '
+	}, name)
+	return strings.Join(strings.Fields(cleaned), " ")
+}
+
+// authorBase drops the middle initials from a NormalizeAuthor key,
+// reporting whether there were any
+func authorBase(key string) (string, bool) {
+	words := strings.Fields(key)
+	if len(words) <= 2 {
+		return key, false
+	}
+
+	kept := []string{words[0]}
+	for _, w := range words[1 : len(words)-1] {
+		if len([]rune(w)) > 1 {
+			kept = append(kept, w)
+		}
+	}
+	kept = append(kept, words[len(words)-1])
+	return strings.Join(kept, " "), len(kept) < len(words)
+}
+
+// SameAuthor reports whether two names are the same author: their keys
+// are equal, or differ only in middle initials one of them leaves out, as
+// "Octavia E. Butler" and "Octavia Butler" do. "John A. Smith" and "John
+// B. Smith" are different people.
+func SameAuthor(a, b string) bool {
+	ka, kb := NormalizeAuthor(a), NormalizeAuthor(b)
+	if ka == kb {
+		return true
+	}
+	baseA, initialsA := authorBase(ka)
+	baseB, initialsB := authorBase(kb)
+	return baseA == baseB && !(initialsA && initialsB)
+}
+
+// AuthorKeys maps each name to the key it is grouped under. A name
+// without middle initials joins the one name that has them and otherwise
+// matches; when several such names differ in their initials, each stays
+// apart.
+func AuthorKeys(names []string) map[string]string {
+	variants := make(map[string]map[string]bool)
+	for _, name := range names {
+		key := NormalizeAuthor(name)
+		if base, initials := authorBase(key); initials {
+			if variants[base] == nil {
+				variants[base] = make(map[string]bool)
+			}
+			variants[base][key] = true
+		}
+	}
+
+	keys := make(map[string]string, len(names))
+	for _, name := range names {
+		key := NormalizeAuthor(name)
+		if base, initials := authorBase(key); !initials && len(variants[base]) == 1 {
+			for full := range variants[base] {
+				key = full
+			}
+		}
+		keys[name] = key
+	}
+	return keys
+}
+
+// bookAuthorKeys groups the primary authors of books (see AuthorKeys)
+func bookAuthorKeys(books []Book) map[string]string {
+	names := make([]string, len(books))
+	for i, book := range books {
+		names[i] = book.Author
+	}
+	return AuthorKeys(names)
+}
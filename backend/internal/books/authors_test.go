@@ -0,0 +1,64 @@
+package books
+
+import (
+	"testing"
+)
+
+// TestSameAuthor verifies name variants match and different middle
+// initials don't
+func TestSameAuthor(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"Octavia E. Butler", "Octavia Butler", true},
+		{"octavia  butler", "Octavia Butler", true},
+		{"N.K. Jemisin", "N. K. Jemisin", true},
+		{"Ursula K. Le Guin", "Ursula Le Guin", true},
+		{"Octavia Butler", "Judith Butler", false},
+		{"John A. Smith", "John B. Smith", false},
+		{"John A. Smith", "John Smith", true},
+	}
+
+	for _, tt := range tests {
+		if got := SameAuthor(tt.a, tt.b); got != tt.same {
+			t.Errorf("SameAuthor(%q, %q): got %v, want %v", tt.a, tt.b, got, tt.same)
+		}
+	}
+}
+
+// TestAuthorKeys verifies a name without initials joins the one name with
+// them, and stays apart when the initials are ambiguous
+func TestAuthorKeys(t *testing.T) {
+	keys := AuthorKeys([]string{"Octavia E. Butler", "Octavia Butler", "John A. Smith", "John B. Smith", "John Smith"})
+
+	if keys["Octavia Butler"] != keys["Octavia E. Butler"] {
+		t.Errorf("Expected Octavia Butler grouped with Octavia E. Butler, got %q and %q",
+			keys["Octavia Butler"], keys["Octavia E. Butler"])
+	}
+	if keys["John A. Smith"] == keys["John B. Smith"] {
+		t.Errorf("Expected John A. Smith and John B. Smith apart, both got %q", keys["John A. Smith"])
+	}
+	if keys["John Smith"] == keys["John A. Smith"] || keys["John Smith"] == keys["John B. Smith"] {
+		t.Errorf("Expected John Smith apart from both, got %q", keys["John Smith"])
+	}
+}
+
+// TestParseAuthorCredits verifies primary and additional authors with roles
+func TestParseAuthorCredits(t *testing.T) {
+	credits := ParseAuthorCredits("Cixin Liu", "Ken Liu (Translator), Joel Martinsen (translator), Cixin Liu")
+
+	want := []AuthorCredit{
+		{Name: "Cixin Liu", Role: "author"},
+		{Name: "Ken Liu", Role: "translator"},
+		{Name: "Joel Martinsen", Role: "translator"},
+	}
+	if len(credits) != len(want) {
+		t.Fatalf("Expected %d credits, got %+v", len(want), credits)
+	}
+	for i := range want {
+		if credits[i] != want[i] {
+			t.Errorf("Credit %d: got %+v, want %+v", i, credits[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package books
+
+import (
+	"strconv"
+	"strings"
+)
+
+// nameParticles join the word after them in a family name, as in
+// "Ursula K. Le Guin" or "Ludwig van Beethoven"
+var nameParticles = map[string]bool{
+	"da": true, "de": true, "del": true, "della": true, "der": true, "di": true,
+	"du": true, "la": true, "le": true, "van": true, "von": true,
+}
+
+// titleArticles are skipped when picking a title word for a citation key
+var titleArticles = map[string]bool{"a": true, "an": true, "the": true}
+
+// SplitName divides a name into given names and a family name, keeping
+// particles such as "Le" or "van" with the family name
+func SplitName(name string) (given, family string) {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return "", ""
+	}
+	i := len(words) - 1
+	for i > 1 && nameParticles[strings.ToLower(words[i-1])] {
+		i--
+	}
+	return strings.Join(words[:i], " "), strings.Join(words[i:], " ")
+}
+
+// keyWord lowercases s and keeps only ASCII letters and digits
+func keyWord(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// CitationKey returns a citation key such as "butler1979kindred" from the
+// first author's family name, the year ("nd" when unknown) and the first
+// title word that isn't an article
+func CitationKey(author, title string, year int) string {
+	_, family := SplitName(author)
+	key := keyWord(family)
+	if key == "" {
+		key = "anon"
+	}
+	if year > 0 {
+		key += strconv.Itoa(year)
+	} else {
+		key += "nd"
+	}
+	title, _ = ParseSeries(title)
+	for _, word := range strings.Fields(title) {
+		if w := keyWord(word); w != "" && !titleArticles[w] {
+			return key + w
+		}
+	}
+	return key
+}
+
+// CitationSuffix returns the suffix telling apart the nth book sharing a
+// citation key after the first: "b" to "z", then "aa", "ab" and so on
+func CitationSuffix(n int) string {
+	var suffix []byte
+	for n++; n > 0; n = (n - 1) / 26 {
+		suffix = append([]byte{byte('a' + (n-1)%26)}, suffix...)
+	}
+	return string(suffix)
+}
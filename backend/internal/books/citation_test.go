@@ -0,0 +1,31 @@
+package books
+
+import "testing"
+
+// TestCitationKey verifies keys use the family name, year and first title word
+func TestCitationKey(t *testing.T) {
+	tests := []struct {
+		author, title string
+		year          int
+		want          string
+	}{
+		{"Octavia E. Butler", "Kindred", 1979, "butler1979kindred"},
+		{"Ursula K. Le Guin", "The Dispossessed", 1974, "leguin1974dispossessed"},
+		{"Cixin Liu", "The Three-Body Problem (Remembrance of Earth's Past, #1)", 2014, "liu2014threebody"},
+		{"", "A", 0, "anonnd"},
+	}
+	for _, tt := range tests {
+		if got := CitationKey(tt.author, tt.title, tt.year); got != tt.want {
+			t.Errorf("CitationKey(%q, %q, %d) = %q, want %q", tt.author, tt.title, tt.year, got, tt.want)
+		}
+	}
+}
+
+// TestCitationSuffix verifies suffixes run b to z and then use two letters
+func TestCitationSuffix(t *testing.T) {
+	for n, want := range map[int]string{1: "b", 25: "z", 26: "aa", 27: "ab", 701: "zz", 702: "aaa"} {
+		if got := CitationSuffix(n); got != want {
+			t.Errorf("CitationSuffix(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package books
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Clipping kinds as recorded by a Kindle
+const (
+	ClippingHighlight = "highlight"
+	ClippingNote      = "note"
+	ClippingBookmark  = "bookmark"
+)
+
+// Clipping is one entry from a Kindle "My Clippings.txt" file
+type Clipping struct {
+	Title    string
+	Author   string
+	Kind     string
+	Page     int
+	Location string // e.g. "1234-1240"
+	AddedAt  time.Time
+	Text     string
+}
+
+// clippingSeparator ends every entry in My Clippings.txt
+const clippingSeparator = "=========="
+
+var (
+	// clippingKind matches "Your Highlight", "Highlight" (older firmware)
+	clippingKind = regexp.MustCompile(`(?i)\b(highlight|note|bookmark)\b`)
+	// clippingPage matches "page 12"
+	clippingPage = regexp.MustCompile(`(?i)\bpage\s+(\d+)`)
+	// clippingLocation matches "Location 180-182", "location 180" or "Loc. 180-82"
+	clippingLocation = regexp.MustCompile(`(?i)\b(?:location|loc\.)\s+(\d+(?:-\d+)?)`)
+	// clippingTitleAuthor splits "Title (Author)" on the last parenthetical
+	clippingTitleAuthor = regexp.MustCompile(`^(.*)\(([^()]*)\)\s*$`)
+)
+
+// clippingTimeLayouts are the "Added on" formats written by Kindle firmware
+var clippingTimeLayouts = []string{
+	"Monday, January 2, 2006 3:04:05 PM",
+	"Monday, January 2, 2006, 3:04:05 PM",
+	"Monday, 2 January 2006 15:04:05",
+	"Monday, January 2, 2006 15:04:05",
+	"Monday, January 02, 2006, 03:04 PM",
+}
+
+// ParseClippings parses a Kindle "My Clippings.txt" file. Entries that cannot
+// be read are skipped. Highlights that were edited on the device appear more
+// than once; only the most recent version of an overlapping highlight is
+// kept, and exact duplicates are dropped.
+func ParseClippings(r io.Reader) ([]Clipping, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var clippings []Clipping
+	var entry []string
+	for scanner.Scan() {
+		line := strings.TrimRight(strings.TrimPrefix(scanner.Text(), "\ufeff"), "\r")
+		if strings.TrimSpace(line) == clippingSeparator {
+			if c, ok := parseClipping(entry); ok {
+				clippings = append(clippings, c)
+			}
+			entry = entry[:0]
+			continue
+		}
+		entry = append(entry, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if c, ok := parseClipping(entry); ok {
+		clippings = append(clippings, c)
+	}
+
+	return dedupeClippings(clippings), nil
+}
+
+// parseClipping parses the lines between two separators: a title line, a
+// metadata line, a blank line and the clipped text
+func parseClipping(lines []string) (Clipping, bool) {
+	// Skip leading blank lines
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) < 2 {
+		return Clipping{}, false
+	}
+
+	var c Clipping
+	c.Title, c.Author = splitClippingTitle(strings.TrimSpace(lines[0]))
+
+	meta := lines[1]
+	kind := clippingKind.FindStringSubmatch(meta)
+	if kind == nil {
+		return Clipping{}, false
+	}
+	c.Kind = strings.ToLower(kind[1])
+	if m := clippingPage.FindStringSubmatch(meta); m != nil {
+		c.Page, _ = strconv.Atoi(m[1])
+	}
+	if m := clippingLocation.FindStringSubmatch(meta); m != nil {
+		c.Location = expandLocation(m[1])
+	}
+	if i := strings.Index(meta, "Added on "); i >= 0 {
+		added := strings.TrimSpace(meta[i+len("Added on "):])
+		for _, layout := range clippingTimeLayouts {
+			if t, err := time.Parse(layout, added); err == nil {
+				c.AddedAt = t
+				break
+			}
+		}
+	}
+
+	c.Text = strings.TrimSpace(strings.Join(lines[2:], "\n"))
+	if c.Kind != ClippingBookmark && c.Text == "" {
+		return Clipping{}, false
+	}
+	return c, true
+}
+
+// splitClippingTitle separates "Title (Author)". Authors written
+// "Last, First" are turned around and only the first of several
+// semicolon-separated authors is kept.
+func splitClippingTitle(line string) (string, string) {
+	m := clippingTitleAuthor.FindStringSubmatch(line)
+	if m == nil {
+		return line, ""
+	}
+	title := strings.TrimSpace(m[1])
+	author := strings.TrimSpace(strings.Split(m[2], ";")[0])
+	if parts := strings.Split(author, ","); len(parts) == 2 {
+		author = strings.TrimSpace(parts[1]) + " " + strings.TrimSpace(parts[0])
+	}
+	return title, author
+}
+
+// expandLocation turns an abbreviated range such as "180-82" into "180-182"
+func expandLocation(loc string) string {
+	start, end, ok := strings.Cut(loc, "-")
+	if !ok || len(end) >= len(start) {
+		return loc
+	}
+	return start + "-" + start[:len(start)-len(end)] + end
+}
+
+// locationRange returns the numeric bounds of a location
+func locationRange(loc string) (int, int, bool) {
+	start, end, found := strings.Cut(loc, "-")
+	s, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, 0, false
+	}
+	if !found {
+		return s, s, true
+	}
+	e, err := strconv.Atoi(end)
+	if err != nil {
+		return s, s, true
+	}
+	return s, e, true
+}
+
+// dedupeClippings drops repeated clippings. Highlights of the same book
+// whose locations overlap are edits of one another: the later one wins.
+func dedupeClippings(clippings []Clipping) []Clipping {
+	var kept []Clipping
+	for _, c := range clippings {
+		replaced := false
+		for i, k := range kept {
+			if k.Title != c.Title || k.Kind != c.Kind {
+				continue
+			}
+			if k.Location == c.Location && k.Page == c.Page && k.Text == c.Text {
+				replaced = true
+				break
+			}
+			if c.Kind == ClippingHighlight && locationsOverlap(k.Location, c.Location) {
+				if !c.AddedAt.Before(k.AddedAt) {
+					kept[i] = c
+				}
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// locationsOverlap reports whether two location ranges share a location
+func locationsOverlap(a, b string) bool {
+	as, ae, ok := locationRange(a)
+	if !ok {
+		return false
+	}
+	bs, be, ok := locationRange(b)
+	if !ok {
+		return false
+	}
+	return as <= be && bs <= ae
+}
+
+// NormalizeTitle returns a matching key for a book title so that
+// "The Fifth Season (The Broken Earth, #1)", "Fifth Season: A Novel" and
+// "the fifth season" compare equal. Series and subtitles, case,
+// punctuation and a leading article are ignored.
+func NormalizeTitle(title string) string {
+	if i := strings.Index(title, "("); i > 0 {
+		title = title[:i]
+	}
+	if i := strings.Index(title, ":"); i > 0 {
+		title = title[:i]
+	}
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		if r == '\'' || r == '’' {
+			return -1
+		}
+		return ' '
+	}, title)
+
+	words := strings.Fields(cleaned)
+	if len(words) > 1 && (words[0] == "the" || words[0] == "a" || words[0] == "an") {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,93 @@
+package books
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleClippings = "\ufeffThe Fifth Season (The Broken Earth, #1) (Jemisin, N. K.)\r\n" +
+	"- Your Highlight on page 12 | Location 180-182 | Added on Monday, March 4, 2024 9:15:32 PM\r\n" +
+	"\r\n" +
+	"Let's start with the end of the world\r\n" +
+	"==========\r\n" +
+	"The Fifth Season (The Broken Earth, #1) (Jemisin, N. K.)\r\n" +
+	"- Your Highlight on page 12 | Location 180-184 | Added on Monday, March 4, 2024 9:16:01 PM\r\n" +
+	"\r\n" +
+	"Let's start with the end of the world, why don't we?\r\n" +
+	"==========\r\n" +
+	"The Fifth Season (The Broken Earth, #1) (Jemisin, N. K.)\r\n" +
+	"- Your Note on page 12 | Location 184 | Added on Monday, March 4, 2024 9:17:10 PM\r\n" +
+	"\r\n" +
+	"Second person narrator!\r\n" +
+	"==========\r\n" +
+	"Kindred (Octavia E. Butler)\r\n" +
+	"- Your Bookmark at location 700 | Added on Tuesday, March 5, 2024 7:00:00 AM\r\n" +
+	"\r\n" +
+	"\r\n" +
+	"==========\r\n" +
+	"Kindred (Octavia E. Butler)\r\n" +
+	"- Highlight Loc. 1020-24 | Added on Tuesday, March 5, 2024 7:30:00 AM\r\n" +
+	"\r\n" +
+	"I lost an arm on my last trip home.\r\n" +
+	"==========\r\n" +
+	"Kindred (Octavia E. Butler)\r\n" +
+	"- Highlight Loc. 1020-24 | Added on Tuesday, March 5, 2024 7:30:00 AM\r\n" +
+	"\r\n" +
+	"I lost an arm on my last trip home.\r\n" +
+	"==========\r\n"
+
+// TestParseClippings verifies kinds, pages, locations, timestamps and authors are read
+func TestParseClippings(t *testing.T) {
+	clippings, err := ParseClippings(strings.NewReader(sampleClippings))
+	if err != nil {
+		t.Fatalf("ParseClippings failed: %v", err)
+	}
+
+	// The edited highlight and the repeated Kindred highlight collapse
+	if len(clippings) != 4 {
+		t.Fatalf("Expected 4 clippings, got %d: %+v", len(clippings), clippings)
+	}
+
+	h := clippings[0]
+	if h.Title != "The Fifth Season (The Broken Earth, #1)" || h.Author != "N. K. Jemisin" {
+		t.Errorf("Expected title and author split, got %q / %q", h.Title, h.Author)
+	}
+	if h.Kind != ClippingHighlight || h.Page != 12 || h.Location != "180-184" {
+		t.Errorf("Expected edited highlight at 180-184, got %+v", h)
+	}
+	if !strings.HasSuffix(h.Text, "why don't we?") {
+		t.Errorf("Expected the later edit to win, got %q", h.Text)
+	}
+	if h.AddedAt.Format("2006-01-02 15:04:05") != "2024-03-04 21:16:01" {
+		t.Errorf("Expected timestamp 2024-03-04 21:16:01, got %v", h.AddedAt)
+	}
+
+	if clippings[1].Kind != ClippingNote || clippings[1].Location != "184" {
+		t.Errorf("Expected note at 184, got %+v", clippings[1])
+	}
+	if clippings[2].Kind != ClippingBookmark || clippings[2].Location != "700" {
+		t.Errorf("Expected bookmark at 700, got %+v", clippings[2])
+	}
+	if clippings[3].Location != "1020-1024" {
+		t.Errorf("Expected abbreviated location expanded to 1020-1024, got %q", clippings[3].Location)
+	}
+}
+
+// TestNormalizeTitle verifies series, subtitles, case and articles are ignored
+func TestNormalizeTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"The Fifth Season (The Broken Earth, #1)", "fifth season"},
+		{"Fifth Season: A Novel", "fifth season"},
+		{"the fifth  season", "fifth season"},
+		{"Ender's Game", "enders game"},
+		{"A", "a"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeTitle(tt.title); got != tt.want {
+			t.Errorf("NormalizeTitle(%q): got %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
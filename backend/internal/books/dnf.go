@@ -0,0 +1,124 @@
+package books
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ShelfDNF is the shelf for books we stopped reading
+const ShelfDNF = "dnf"
+
+// dnfShelfNames are Goodreads shelf names treated as did-not-finish
+var dnfShelfNames = map[string]bool{
+	"dnf":            true,
+	"did-not-finish": true,
+	"didnt-finish":   true,
+	"abandoned":      true,
+	"gave-up":        true,
+	"unfinished":     true,
+}
+
+// NormalizeShelf lowercases a shelf name and maps the common
+// did-not-finish spellings to ShelfDNF
+func NormalizeShelf(shelf string) string {
+	shelf = strings.ToLower(strings.TrimSpace(shelf))
+	shelf = strings.ReplaceAll(shelf, " ", "-")
+	if dnfShelfNames[shelf] {
+		return ShelfDNF
+	}
+	return shelf
+}
+
+// HasDNFShelf reports whether a comma-separated Goodreads Bookshelves
+// value includes a did-not-finish shelf
+func HasDNFShelf(bookshelves string) bool {
+	for _, shelf := range strings.Split(bookshelves, ",") {
+		if NormalizeShelf(shelf) == ShelfDNF {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDNFPage returns the page we stopped at
+func (b *Book) GetDNFPage() int {
+	return toCount(b.DNFPage)
+}
+
+// GetDNFPercent returns how far through the book we stopped, 0-100
+func (b *Book) GetDNFPercent() float64 {
+	switch v := b.DNFPercent.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+		return f
+	default:
+		return 0
+	}
+}
+
+// PagesBeforeStopping returns pages read before abandoning the book, from
+// the stopping page or, failing that, the percent of its page count
+func (b *Book) PagesBeforeStopping() int {
+	if page := b.GetDNFPage(); page > 0 {
+		return page
+	}
+	if pct := b.GetDNFPercent(); pct > 0 {
+		return int(math.Round(float64(b.GetPages()) * pct / 100))
+	}
+	return 0
+}
+
+// ReasonCount is how often a reason was given for abandoning books
+type ReasonCount struct {
+	Reason string
+	Count  int
+}
+
+// DNFStats summarises books abandoned in a year
+type DNFStats struct {
+	Count     int
+	Rate      float64 // abandoned / (abandoned + finished), 0-1
+	PagesRead int     // pages read before abandoning
+	Reasons   []ReasonCount
+}
+
+// CalculateDNFStats reports on books abandoned in year. finished is the
+// number of books read in the same year, used for the DNF rate.
+func CalculateDNFStats(books []Book, year, finished int) DNFStats {
+	var stats DNFStats
+	reasons := make(map[string]int)
+
+	for _, book := range FilterByShelf(books, ShelfDNF) {
+		date, err := ParseDate(book.DNFDate)
+		if err != nil || date.Year != year {
+			continue
+		}
+		stats.Count++
+		stats.PagesRead += book.PagesBeforeStopping()
+		if reason := strings.TrimSpace(book.DNFReason); reason != "" {
+			reasons[reason]++
+		}
+	}
+
+	if total := stats.Count + finished; total > 0 {
+		stats.Rate = float64(stats.Count) / float64(total)
+	}
+
+	for reason, count := range reasons {
+		stats.Reasons = append(stats.Reasons, ReasonCount{Reason: reason, Count: count})
+	}
+	sort.Slice(stats.Reasons, func(i, j int) bool {
+		if stats.Reasons[i].Count != stats.Reasons[j].Count {
+			return stats.Reasons[i].Count > stats.Reasons[j].Count
+		}
+		return stats.Reasons[i].Reason < stats.Reasons[j].Reason
+	})
+
+	return stats
+}
@@ -0,0 +1,131 @@
+package books
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNormalizeShelf verifies did-not-finish spellings map to the dnf shelf
+func TestNormalizeShelf(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"dnf", ShelfDNF},
+		{"DNF", ShelfDNF},
+		{"did-not-finish", ShelfDNF},
+		{"Did Not Finish", ShelfDNF},
+		{"abandoned", ShelfDNF},
+		{"read", "read"},
+		{"to-read", "to-read"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeShelf(tt.input); got != tt.want {
+			t.Errorf("NormalizeShelf(%q): got %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestFilterByShelfDNFAliases verifies FilterByShelf matches shelf aliases
+func TestFilterByShelfDNFAliases(t *testing.T) {
+	books := []Book{
+		{Title: "A", Shelf: "did-not-finish"},
+		{Title: "B", Shelf: "dnf"},
+		{Title: "C", Shelf: "read"},
+	}
+
+	if got := len(FilterByShelf(books, "dnf")); got != 2 {
+		t.Errorf("Expected 2 dnf books, got %d", got)
+	}
+}
+
+// TestCalculateDNFStats verifies rate, pages and reasons for abandoned books
+func TestCalculateDNFStats(t *testing.T) {
+	books := []Book{
+		{Title: "Slow", Shelf: "dnf", DNFDate: "2025/03/01", DNFPage: float64(80), DNFReason: "Too slow"},
+		{Title: "Dense", Shelf: "dnf", DNFDate: "2025/06/01", Pages: float64(400), DNFPercent: float64(25), DNFReason: "Too slow"},
+		{Title: "Bored", Shelf: "abandoned", DNFDate: "2025/07/01", DNFReason: "Lost interest"},
+		{Title: "Old", Shelf: "dnf", DNFDate: "2024/01/01"},
+		{Title: "Finished", Shelf: "read", DateRead: "2025/02/01"},
+	}
+
+	// Seven books finished in the same year
+	stats := CalculateDNFStats(books, 2025, 7)
+
+	if stats.Count != 3 {
+		t.Errorf("Count: got %d, want 3", stats.Count)
+	}
+	if stats.Rate != 0.3 {
+		t.Errorf("Rate: got %v, want 0.3", stats.Rate)
+	}
+	if stats.PagesRead != 180 {
+		t.Errorf("PagesRead: got %d, want 180 (80 + 25%% of 400)", stats.PagesRead)
+	}
+	if len(stats.Reasons) != 2 || stats.Reasons[0].Reason != "Too slow" || stats.Reasons[0].Count != 2 {
+		t.Errorf("Reasons: got %+v", stats.Reasons)
+	}
+}
+
+// TestFilterByYearPlacesDNFByStopDate verifies abandoned books use their stop date
+func TestFilterByYearPlacesDNFByStopDate(t *testing.T) {
+	books := []Book{
+		{Title: "Abandoned", Shelf: "dnf", DNFDate: "2025/04/10"},
+		{Title: "Read", Shelf: "read", DateRead: "2025/05/01"},
+	}
+
+	filtered, _ := FilterByYear(books, 2025)
+	if len(filtered) != 2 {
+		t.Fatalf("Expected 2 books in 2025, got %d", len(filtered))
+	}
+
+	// But the read shelf excludes the abandoned book from book counts
+	if got := len(FilterByShelf(filtered, "read")); got != 1 {
+		t.Errorf("Expected 1 read book, got %d", got)
+	}
+}
+
+// TestFilterByYearKeepsEarlierReadsOfDNF verifies finishing a book before
+// abandoning a re-read still counts the finished read
+func TestFilterByYearKeepsEarlierReadsOfDNF(t *testing.T) {
+	books := []Book{
+		{Title: "Reread", Shelf: "dnf", DNFDate: "2025/04/10", DateRead: "2023/06/01",
+			Reads: []Read{{DateFinished: "2023/06/01"}, {DateStarted: "2025/03/01"}}},
+	}
+
+	earlier, _ := FilterByYear(books, 2023)
+	if len(earlier) != 1 || earlier[0].Shelf != "read" || earlier[0].DateRead != "2023/06/01" {
+		t.Errorf("Expected the 2023 read counted as read, got %+v", earlier)
+	}
+
+	abandoned, _ := FilterByYear(books, 2025)
+	if len(abandoned) != 1 || abandoned[0].Shelf != "dnf" || abandoned[0].DateRead != "" {
+		t.Errorf("Expected only the abandoned attempt in 2025, got %+v", abandoned)
+	}
+}
+
+// TestFinishedReadsAgreeAcrossViews verifies year summaries and author
+// novelty count the same finished reads as FilterByYear's read entries
+func TestFinishedReadsAgreeAcrossViews(t *testing.T) {
+	books := []Book{
+		{Title: "Reread", Author: "A", Shelf: "did not finish", DNFDate: "2025/04/10",
+			Reads: []Read{{DateFinished: "2023/06/01"}, {DateStarted: "2025/03/01"}}},
+		{Title: "Finished", Author: "B", Shelf: "read", DateRead: "2023/02/01"},
+		{Title: "Reading", Author: "C", Shelf: "currently-reading", DateRead: "2023/03/01"},
+	}
+
+	byYear, _ := FilterByYear(books, 2023)
+	read := FilterByShelf(byYear, "read")
+	if len(read) != 2 {
+		t.Fatalf("Expected 2 books read in 2023, got %d", len(read))
+	}
+
+	summary := CalculateSummary(books, nil, time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))
+	if len(summary.Years) == 0 || summary.Years[0].Year != 2023 || summary.Years[0].Books != len(read) {
+		t.Errorf("Expected the summary to count %d books in 2023, got %+v", len(read), summary.Years)
+	}
+
+	if newAuthors, returning := CalculateAuthorNovelty(books, 2023); newAuthors != 2 || returning != 0 {
+		t.Errorf("Expected 2 new authors in 2023, got %d new and %d returning", newAuthors, returning)
+	}
+}
@@ -0,0 +1,148 @@
+package books
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ReadingSpan is a read with both a full start and finish date
+type ReadingSpan struct {
+	Book     Book
+	Started  time.Time
+	Finished time.Time
+	Days     int // calendar days from start to finish, inclusive
+}
+
+// FinishStats summarises how long books took to read in a year
+type FinishStats struct {
+	Count             int // reads with known start and finish dates
+	MeanDays          float64
+	MedianDays        float64
+	Longest           []ReadingSpan // up to five, longest first
+	MaxConcurrent     int           // most books in progress on a single day
+	AverageConcurrent float64       // mean books in progress over days with any
+}
+
+// AllReads returns the book's reads, or a single read built from
+// DateStarted/DateRead when none are recorded
+func (b *Book) AllReads() []Read {
+	if len(b.Reads) > 0 {
+		return b.Reads
+	}
+	if b.DateRead == "" && b.DateStarted == "" {
+		return nil
+	}
+	return []Read{{DateStarted: b.DateStarted, DateFinished: b.DateRead}}
+}
+
+// Time converts a full date to a time.Time. It reports false when the
+// month or day is missing.
+func (d ParsedDate) Time() (time.Time, bool) {
+	if d.Month == 0 || d.Day == 0 {
+		return time.Time{}, false
+	}
+	return time.Date(d.Year, time.Month(d.Month), d.Day, 0, 0, 0, 0, time.UTC), true
+}
+
+// String formats the date as a zero-padded YYYY, YYYY/MM or YYYY/MM/DD,
+// so stored dates sort correctly as strings
+func (d ParsedDate) String() string {
+	switch {
+	case d.Month == 0:
+		return fmt.Sprintf("%04d", d.Year)
+	case d.Day == 0:
+		return fmt.Sprintf("%04d/%02d", d.Year, d.Month)
+	default:
+		return fmt.Sprintf("%04d/%02d/%02d", d.Year, d.Month, d.Day)
+	}
+}
+
+// parseFullDate parses a YYYY/MM/DD string, reporting false for partial dates
+func parseFullDate(s string) (time.Time, bool) {
+	d, err := ParseDate(s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return d.Time()
+}
+
+// ReadingSpans returns the reads finished in year that have full start and
+// finish dates. Reads finishing before they start are skipped.
+func ReadingSpans(books []Book, year int) []ReadingSpan {
+	var spans []ReadingSpan
+	for _, book := range books {
+		for _, r := range book.AllReads() {
+			started, ok := parseFullDate(r.DateStarted)
+			if !ok {
+				continue
+			}
+			finished, ok := parseFullDate(r.DateFinished)
+			if !ok || finished.Year() != year || finished.Before(started) {
+				continue
+			}
+			spans = append(spans, ReadingSpan{
+				Book:     book,
+				Started:  started,
+				Finished: finished,
+				Days:     int(finished.Sub(started).Hours()/24) + 1,
+			})
+		}
+	}
+	return spans
+}
+
+// CalculateFinishStats calculates time-to-finish and reading overlap for
+// reads finished in a year
+func CalculateFinishStats(books []Book, year int) FinishStats {
+	spans := ReadingSpans(books, year)
+	stats := FinishStats{Count: len(spans)}
+	if len(spans) == 0 {
+		return stats
+	}
+
+	days := make([]int, len(spans))
+	total := 0
+	for i, s := range spans {
+		days[i] = s.Days
+		total += s.Days
+	}
+	sort.Ints(days)
+	stats.MeanDays = float64(total) / float64(len(days))
+	mid := len(days) / 2
+	if len(days)%2 == 0 {
+		stats.MedianDays = float64(days[mid-1]+days[mid]) / 2
+	} else {
+		stats.MedianDays = float64(days[mid])
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Days > spans[j].Days })
+	if len(spans) > 5 {
+		stats.Longest = spans[:5]
+	} else {
+		stats.Longest = spans
+	}
+
+	// Count books in progress per day, clipped to the year
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	inProgress := make(map[int]int)
+	for _, s := range spans {
+		start := s.Started
+		if start.Before(yearStart) {
+			start = yearStart
+		}
+		for d := start; !d.After(s.Finished); d = d.AddDate(0, 0, 1) {
+			inProgress[d.YearDay()]++
+		}
+	}
+	sum := 0
+	for _, n := range inProgress {
+		sum += n
+		if n > stats.MaxConcurrent {
+			stats.MaxConcurrent = n
+		}
+	}
+	stats.AverageConcurrent = float64(sum) / float64(len(inProgress))
+
+	return stats
+}
@@ -0,0 +1,83 @@
+package books
+
+import (
+	"testing"
+)
+
+// TestCalculateFinishStats verifies mean, median and longest reads
+func TestCalculateFinishStats(t *testing.T) {
+	// Given reads of 1, 10 and 31 days finished in 2025
+	books := []Book{
+		{Title: "Quick", DateStarted: "2025/01/05", DateRead: "2025/01/05"},
+		{Title: "Medium", DateStarted: "2025/02/01", DateRead: "2025/02/10"},
+		{Title: "Long", DateStarted: "2025/03/01", DateRead: "2025/03/31"},
+		{Title: "No start", DateRead: "2025/04/01"},
+		{Title: "Last year", DateStarted: "2024/05/01", DateRead: "2024/05/20"},
+	}
+
+	// When calculating finish stats for 2025
+	stats := CalculateFinishStats(books, 2025)
+
+	// Then only fully dated 2025 reads should count
+	if stats.Count != 3 {
+		t.Fatalf("Count: got %d, want 3", stats.Count)
+	}
+	if stats.MeanDays != 14 {
+		t.Errorf("MeanDays: got %v, want 14", stats.MeanDays)
+	}
+	if stats.MedianDays != 10 {
+		t.Errorf("MedianDays: got %v, want 10", stats.MedianDays)
+	}
+	if stats.Longest[0].Book.GetTitle() != "Long" || stats.Longest[0].Days != 31 {
+		t.Errorf("Longest: got %q (%d days), want Long (31 days)",
+			stats.Longest[0].Book.GetTitle(), stats.Longest[0].Days)
+	}
+
+	// And no books overlapped
+	if stats.MaxConcurrent != 1 {
+		t.Errorf("MaxConcurrent: got %d, want 1", stats.MaxConcurrent)
+	}
+}
+
+// TestCalculateFinishStatsOverlap verifies concurrent reads are detected
+func TestCalculateFinishStatsOverlap(t *testing.T) {
+	books := []Book{
+		{Title: "A", DateStarted: "2025/06/01", DateRead: "2025/06/10"},
+		{Title: "B", DateStarted: "2025/06/05", DateRead: "2025/06/08"},
+		{Title: "C", DateStarted: "2025/06/07", DateRead: "2025/06/20"},
+		// Started the previous year; only 2025 days count toward overlap
+		{Title: "D", DateStarted: "2024/12/20", DateRead: "2025/01/02"},
+	}
+
+	stats := CalculateFinishStats(books, 2025)
+
+	if stats.MaxConcurrent != 3 {
+		t.Errorf("MaxConcurrent: got %d, want 3", stats.MaxConcurrent)
+	}
+	if stats.AverageConcurrent <= 1 {
+		t.Errorf("AverageConcurrent: got %v, want more than 1", stats.AverageConcurrent)
+	}
+}
+
+// TestCalculateFinishStatsUsesReads verifies each recorded read is measured
+func TestCalculateFinishStatsUsesReads(t *testing.T) {
+	books := []Book{
+		{
+			Title:    "Reread",
+			DateRead: "2025/09/04",
+			Reads: []Read{
+				{DateStarted: "2025/01/01", DateFinished: "2025/01/02"},
+				{DateStarted: "2025/09/01", DateFinished: "2025/09/04"},
+			},
+		},
+	}
+
+	stats := CalculateFinishStats(books, 2025)
+
+	if stats.Count != 2 {
+		t.Errorf("Count: got %d, want 2", stats.Count)
+	}
+	if stats.MedianDays != 3 {
+		t.Errorf("MedianDays: got %v, want 3", stats.MedianDays)
+	}
+}
@@ -1,33 +1,60 @@
 package books
 
-// FilterByYear filters books by year from DateRead field
+// FilterByYear filters books by the year they were read. A book read more
+// than once in the year appears once per read, with DateRead (and Format,
+// when the read records one) taken from that read. An abandoned book is
+// placed by the date we stopped reading; reads of it we did finish earlier
+// still count as read in their own years. The entries left on the read
+// shelf are exactly the books' FinishedReads in the year.
 func FilterByYear(books []Book, year int) ([]Book, error) {
 	var filtered []Book
 	
 	for _, book := range books {
-		if book.DateRead == "" {
-			continue
+		dnf := NormalizeShelf(book.Shelf) == ShelfDNF && book.DNFDate != ""
+		if dnf {
+			if date, err := ParseDate(book.DNFDate); err == nil && date.Year == year {
+				abandoned := book
+				abandoned.DateRead = ""
+				filtered = append(filtered, abandoned)
+			}
 		}
 		
-		date, err := ParseDate(book.DateRead)
-		if err != nil {
-			continue // Skip invalid dates
-		}
-		
-		if date.Year == year {
-			filtered = append(filtered, book)
+		for _, r := range book.AllReads() {
+			if r.DateFinished == "" {
+				continue
+			}
+			
+			date, err := ParseDate(r.DateFinished)
+			if err != nil {
+				continue // Skip invalid dates
+			}
+			
+			if date.Year == year {
+				read := book
+				read.DateRead = r.DateFinished
+				if r.Format != "" {
+					read.Format = r.Format
+				}
+				if dnf {
+					read.Shelf = "read"
+					read.DNFDate, read.DNFPage, read.DNFPercent, read.DNFReason = "", nil, nil, ""
+				}
+				filtered = append(filtered, read)
+			}
 		}
 	}
 	
 	return filtered, nil
 }
 
-// FilterByShelf filters books by shelf status
+// FilterByShelf filters books by shelf status. Shelf names are compared
+// after NormalizeShelf, so "did-not-finish" matches "dnf".
 func FilterByShelf(books []Book, shelf string) []Book {
 	var filtered []Book
+	shelf = NormalizeShelf(shelf)
 	
 	for _, book := range books {
-		if book.Shelf == shelf {
+		if NormalizeShelf(book.Shelf) == shelf {
 			filtered = append(filtered, book)
 		}
 	}
@@ -139,3 +139,48 @@ func TestFilterByYearAndShelf(t *testing.T) {
 		}
 	}
 }
+
+// TestFilterByYearCountsRereads verifies each read is counted in its own year
+func TestFilterByYearCountsRereads(t *testing.T) {
+	// Given a book read in 2023 and twice in 2025
+	books := []Book{
+		{
+			Title:    "Parable of the Sower",
+			Author:   "Octavia E. Butler",
+			DateRead: "2025/11/02",
+			Shelf:    "read",
+			Reads: []Read{
+				{DateFinished: "2023/04/10"},
+				{DateFinished: "2025/01/15"},
+				{DateFinished: "2025/11/02"},
+				{DateFinished: ""}, // undated Goodreads re-read
+			},
+		},
+	}
+
+	// When filtering by 2025
+	filtered, err := FilterByYear(books, 2025)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Then both 2025 reads should be returned with their own dates
+	if len(filtered) != 2 {
+		t.Fatalf("Expected 2 reads in 2025, got %d", len(filtered))
+	}
+	if filtered[0].DateRead != "2025/01/15" || filtered[1].DateRead != "2025/11/02" {
+		t.Errorf("Unexpected read dates: %q, %q", filtered[0].DateRead, filtered[1].DateRead)
+	}
+
+	// And the statistics should count both
+	stats := CalculateStatistics(filtered, 2025)
+	if stats.TotalBooks != 2 {
+		t.Errorf("TotalBooks: got %d, want 2", stats.TotalBooks)
+	}
+
+	// And 2023 should still count the first read
+	filtered, _ = FilterByYear(books, 2023)
+	if len(filtered) != 1 {
+		t.Errorf("Expected 1 read in 2023, got %d", len(filtered))
+	}
+}
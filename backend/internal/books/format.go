@@ -0,0 +1,102 @@
+package books
+
+import (
+	"math"
+	"strings"
+)
+
+// Book formats
+const (
+	FormatPrint     = "print"
+	FormatEbook     = "ebook"
+	FormatAudiobook = "audiobook"
+)
+
+// DefaultMinutesPerPage converts audiobook listening time to pages, based on
+// roughly 150 spoken words per minute and 300 words per page
+const DefaultMinutesPerPage = 2.0
+
+// NormalizeFormat maps a format or Goodreads binding such as "Paperback",
+// "Kindle Edition" or "Audible Audio" to print, ebook or audiobook.
+// Unrecognised values return "".
+func NormalizeFormat(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case s == "":
+		return ""
+	case strings.Contains(s, "audio"):
+		return FormatAudiobook
+	case strings.Contains(s, "kindle") || strings.Contains(s, "ebook") ||
+		strings.Contains(s, "e-book") || strings.Contains(s, "nook") || strings.Contains(s, "digital"):
+		return FormatEbook
+	case strings.Contains(s, "print") || strings.Contains(s, "paperback") || strings.Contains(s, "hardcover") ||
+		strings.Contains(s, "mass market") || strings.Contains(s, "board book") ||
+		strings.Contains(s, "library binding"):
+		return FormatPrint
+	default:
+		return ""
+	}
+}
+
+// GetFormat returns the book's normalized format, falling back to the binding
+func (b *Book) GetFormat() string {
+	if f := NormalizeFormat(b.Format); f != "" {
+		return f
+	}
+	return NormalizeFormat(b.Binding)
+}
+
+// GetDurationMinutes returns the audiobook length in minutes
+func (b *Book) GetDurationMinutes() int {
+	return toCount(b.DurationMinutes)
+}
+
+// PageEquivalent returns pages for audiobooks that have a duration but no
+// page count, converting listening time at minutesPerPage. Books with a page
+// count, and books of other formats, contribute nothing.
+func PageEquivalent(books []Book, minutesPerPage float64) int {
+	if minutesPerPage <= 0 {
+		return 0
+	}
+	total := 0
+	for _, book := range books {
+		if book.GetFormat() != FormatAudiobook || book.GetPages() > 0 {
+			continue
+		}
+		total += int(math.Round(float64(book.GetDurationMinutes()) / minutesPerPage))
+	}
+	return total
+}
+
+// FormatCount represents reading totals for one format
+type FormatCount struct {
+	Format  string // print, ebook, audiobook, or "" when unknown
+	Books   int
+	Pages   int
+	Minutes int
+}
+
+// CalculateFormatBreakdown totals books, pages and listening minutes per
+// format, in print, ebook, audiobook, unknown order
+func CalculateFormatBreakdown(books []Book) []FormatCount {
+	order := []string{FormatPrint, FormatEbook, FormatAudiobook, ""}
+	counts := make(map[string]*FormatCount, len(order))
+	for _, f := range order {
+		counts[f] = &FormatCount{Format: f}
+	}
+
+	for _, book := range books {
+		c := counts[book.GetFormat()]
+		c.Books++
+		if pages := book.GetPages(); pages > 0 {
+			c.Pages += pages
+		}
+		c.Minutes += book.GetDurationMinutes()
+	}
+
+	breakdown := make([]FormatCount, len(order))
+	for i, f := range order {
+		breakdown[i] = *counts[f]
+	}
+	return breakdown
+}
@@ -0,0 +1,70 @@
+package books
+
+import (
+	"testing"
+)
+
+// TestNormalizeFormat verifies Goodreads bindings map to formats
+func TestNormalizeFormat(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Paperback", FormatPrint},
+		{"Hardcover", FormatPrint},
+		{"Mass Market Paperback", FormatPrint},
+		{"Kindle Edition", FormatEbook},
+		{"ebook", FormatEbook},
+		{"Audible Audio", FormatAudiobook},
+		{"Audio CD", FormatAudiobook},
+		{"audiobook", FormatAudiobook},
+		{"", ""},
+		{"Unknown Binding", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeFormat(tt.input); got != tt.want {
+			t.Errorf("NormalizeFormat(%q): got %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestCalculateFormatBreakdown verifies totals are split by format
+func TestCalculateFormatBreakdown(t *testing.T) {
+	books := []Book{
+		{Title: "Print", Pages: float64(300), Format: "print"},
+		{Title: "Kindle", Pages: float64(200), Binding: "Kindle Edition"},
+		{Title: "Audio", Format: "audiobook", DurationMinutes: float64(600)},
+		{Title: "Mystery", Pages: float64(100)},
+	}
+
+	breakdown := CalculateFormatBreakdown(books)
+
+	want := []FormatCount{
+		{Format: FormatPrint, Books: 1, Pages: 300},
+		{Format: FormatEbook, Books: 1, Pages: 200},
+		{Format: FormatAudiobook, Books: 1, Minutes: 600},
+		{Format: "", Books: 1, Pages: 100},
+	}
+	for i := range want {
+		if breakdown[i] != want[i] {
+			t.Errorf("Format %d: got %+v, want %+v", i, breakdown[i], want[i])
+		}
+	}
+}
+
+// TestPageEquivalent verifies listening time converts only for page-less audiobooks
+func TestPageEquivalent(t *testing.T) {
+	books := []Book{
+		{Title: "Audio", Format: "audiobook", DurationMinutes: float64(600)},
+		{Title: "Audio with pages", Format: "audiobook", Pages: float64(250), DurationMinutes: float64(480)},
+		{Title: "Print", Format: "print", Pages: float64(300)},
+	}
+
+	if got := PageEquivalent(books, DefaultMinutesPerPage); got != 300 {
+		t.Errorf("PageEquivalent: got %d, want 300", got)
+	}
+	if got := PageEquivalent(books, 0); got != 0 {
+		t.Errorf("PageEquivalent with no rate: got %d, want 0", got)
+	}
+}
@@ -3,6 +3,7 @@ package books
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -19,6 +20,7 @@ type Book struct {
 	Pages                    interface{} `json:"Number of Pages"`
 	YearPublished            interface{} `json:"Year Published"`
 	OriginalPublicationYear  interface{} `json:"Original Publication Year"`
+	DateStarted              string      `json:"Date Started,omitempty"`
 	DateRead                 string      `json:"Date Read"`
 	DateAdded                interface{} `json:"Date Added"`
 	Bookshelves              string      `json:"Bookshelves"`
@@ -26,6 +28,28 @@ type Book struct {
 	Shelf                    string      `json:"Shelf"`
 	MyReview                 interface{} `json:"My Review"`
 	CoverURL                 string      `json:"CoverURL,omitempty"`
+	GoodreadsReadCount       interface{} `json:"Read Count,omitempty"`
+	Binding                  string      `json:"Binding,omitempty"`
+	Format                   string      `json:"Format,omitempty"`
+	DurationMinutes          interface{} `json:"Duration Minutes,omitempty"`
+	DNFDate                  string      `json:"DNF Date,omitempty"`
+	DNFPage                  interface{} `json:"DNF Page,omitempty"`
+	DNFPercent               interface{} `json:"DNF Percent,omitempty"`
+	DNFReason                string      `json:"DNF Reason,omitempty"`
+	Notes                    []Note      `json:"Notes,omitempty"`
+	OwnedCopies              interface{} `json:"Owned Copies,omitempty"`
+	Ownership                string      `json:"Ownership,omitempty"`
+	OwnedFormat              string      `json:"Owned Format,omitempty"`
+	PurchaseDate             string      `json:"Purchase Date,omitempty"`
+	PurchasePrice            interface{} `json:"Purchase Price,omitempty"`
+	ShelfLocation            string      `json:"Shelf Location,omitempty"`
+	Rating                   interface{} `json:"My Rating,omitempty"`
+
+	// Fields populated from the database rather than books.json
+	ID             int64   `json:"-"`
+	Series         string  `json:"-"`
+	SeriesPosition float64 `json:"-"`
+	Reads          []Read  `json:"-"`
 }
 
 // GetTitle returns the title as a string
@@ -42,9 +66,60 @@ func (b *Book) GetTitle() string {
 	}
 }
 
+// GetDateAdded returns the date the book was shelved, YYYY/MM/DD
+func (b *Book) GetDateAdded() string {
+	if s, ok := b.DateAdded.(string); ok {
+		return strings.TrimSpace(s)
+	}
+	return ""
+}
+
 // GetPages returns the page count as an integer
 func (b *Book) GetPages() int {
-	switch v := b.Pages.(type) {
+	return toCount(b.Pages)
+}
+
+// GetPublicationYear returns the year the book was first published,
+// falling back to the edition's year, or 0 when neither is known
+func (b *Book) GetPublicationYear() int {
+	if year := toCount(b.OriginalPublicationYear); year > 0 {
+		return year
+	}
+	return toCount(b.YearPublished)
+}
+
+// GetRating returns our star rating from 0.25 to 5 in quarter stars, or 0
+// when unrated
+func (b *Book) GetRating() float64 {
+	var r float64
+	switch v := b.Rating.(type) {
+	case float64:
+		r = v
+	case int:
+		r = float64(v)
+	case string:
+		r, _ = strconv.ParseFloat(strings.TrimSpace(v), 64)
+	}
+	if r <= 0 || r > 5 {
+		return 0
+	}
+	return QuarterStars(r)
+}
+
+// QuarterStars rounds a rating to the nearest quarter star
+func QuarterStars(r float64) float64 {
+	return math.Round(r*4) / 4
+}
+
+// WholeStars rounds a rating to whole stars, for places that can't show
+// quarters such as Goodreads' CSV or a row of ★
+func WholeStars(r float64) int {
+	return int(math.Round(r))
+}
+
+// toCount converts a JSON number or numeric string to an integer
+func toCount(v interface{}) int {
+	switch v := v.(type) {
 	case float64:
 		return int(v)
 	case int:
@@ -60,6 +135,14 @@ func (b *Book) GetPages() int {
 	}
 }
 
+// Note is a quote, highlight or note kept with a book in exported JSON
+type Note struct {
+	Kind     string `json:"Kind"`
+	Text     string `json:"Text"`
+	Page     int    `json:"Page,omitempty"`
+	Location string `json:"Location,omitempty"`
+}
+
 // ParsedDate represents a parsed date
 type ParsedDate struct {
 	Year  int
@@ -256,3 +256,46 @@ func TestParseDateInvalid(t *testing.T) {
 		})
 	}
 }
+
+// TestParsedDateString verifies dates are zero-padded to their precision
+func TestParsedDateString(t *testing.T) {
+	tests := map[string]string{
+		"2025/9/1":   "2025/09/01",
+		"2025/10/15": "2025/10/15",
+		"2025/3":     "2025/03",
+		"2025":       "2025",
+	}
+	for input, want := range tests {
+		date, err := ParseDate(input)
+		if err != nil {
+			t.Fatalf("ParseDate(%q): %v", input, err)
+		}
+		if got := date.String(); got != want {
+			t.Errorf("ParseDate(%q).String() = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// TestGetRating verifies ratings keep quarter stars and stay within 0-5
+func TestGetRating(t *testing.T) {
+	tests := []struct {
+		rating interface{}
+		want   float64
+	}{
+		{float64(4), 4},
+		{3.75, 3.75},
+		{"2.5", 2.5},
+		{3.8, 3.75},
+		{float64(6), 0},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		b := Book{Rating: tt.rating}
+		if got := b.GetRating(); got != tt.want {
+			t.Errorf("GetRating(%v) = %g, want %g", tt.rating, got, tt.want)
+		}
+	}
+	if WholeStars(3.75) != 4 || WholeStars(2.25) != 2 {
+		t.Errorf("Expected quarter stars rounded to the nearest whole star")
+	}
+}
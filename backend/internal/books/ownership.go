@@ -0,0 +1,191 @@
+package books
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Ownership states of a copy
+const (
+	OwnershipOwned    = "owned"
+	OwnershipBorrowed = "borrowed"
+	OwnershipLibrary  = "library"
+	OwnershipWishlist = "wishlist"
+)
+
+// NormalizeOwnership maps an ownership value such as "Own", "Library Loan"
+// or "wish list" to owned, borrowed, library or wishlist. Unrecognised
+// values return "".
+func NormalizeOwnership(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case s == "":
+		return ""
+	case strings.Contains(s, "library"):
+		return OwnershipLibrary
+	case strings.Contains(s, "borrow") || strings.Contains(s, "loan"):
+		return OwnershipBorrowed
+	case strings.Contains(s, "wish") || strings.Contains(s, "want"):
+		return OwnershipWishlist
+	case strings.HasPrefix(s, "own") || s == "bought" || s == "purchased":
+		return OwnershipOwned
+	default:
+		return ""
+	}
+}
+
+// GetOwnership returns the book's normalized ownership. Goodreads exports
+// record owned books as a count of Owned Copies.
+func (b *Book) GetOwnership() string {
+	if o := NormalizeOwnership(b.Ownership); o != "" {
+		return o
+	}
+	if toCount(b.OwnedCopies) > 0 {
+		return OwnershipOwned
+	}
+	return ""
+}
+
+// GetPurchasePrice returns what we paid for our copy
+func (b *Book) GetPurchasePrice() float64 {
+	switch v := b.PurchasePrice.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimLeft(strings.TrimSpace(v), "$£€"), 64)
+		return f
+	default:
+		return 0
+	}
+}
+
+// ValueGroup is the owned books and their value for one format or location
+type ValueGroup struct {
+	Name  string
+	Count int
+	Value float64
+}
+
+// LibraryValue summarises the books we own and what they cost
+type LibraryValue struct {
+	Owned      int
+	Priced     int     // owned books with a purchase price
+	TotalValue float64 // sum of purchase prices
+	AvgPrice   float64 // over priced books
+	ByFormat   []ValueGroup
+	ByLocation []ValueGroup
+	Ownership  map[string]int // books per ownership state, including owned
+}
+
+// CalculateLibraryValue totals the purchase prices of owned books, grouped
+// by owned format and shelf location. Books without a format or location
+// are grouped under "unknown".
+func CalculateLibraryValue(books []Book) LibraryValue {
+	value := LibraryValue{Ownership: make(map[string]int)}
+	byFormat := make(map[string]*ValueGroup)
+	byLocation := make(map[string]*ValueGroup)
+
+	add := func(groups map[string]*ValueGroup, name string, price float64) {
+		if name == "" {
+			name = "unknown"
+		}
+		g, ok := groups[name]
+		if !ok {
+			g = &ValueGroup{Name: name}
+			groups[name] = g
+		}
+		g.Count++
+		g.Value += price
+	}
+
+	for _, book := range books {
+		ownership := book.GetOwnership()
+		if ownership == "" {
+			continue
+		}
+		value.Ownership[ownership]++
+		if ownership != OwnershipOwned {
+			continue
+		}
+
+		price := book.GetPurchasePrice()
+		value.Owned++
+		if price > 0 {
+			value.Priced++
+			value.TotalValue += price
+		}
+		add(byFormat, book.OwnedFormat, price)
+		add(byLocation, strings.TrimSpace(book.ShelfLocation), price)
+	}
+
+	if value.Priced > 0 {
+		value.AvgPrice = roundCents(value.TotalValue / float64(value.Priced))
+	}
+	value.TotalValue = roundCents(value.TotalValue)
+	value.ByFormat = sortValueGroups(byFormat)
+	value.ByLocation = sortValueGroups(byLocation)
+	return value
+}
+
+// sortValueGroups returns groups by descending value, then name
+func sortValueGroups(groups map[string]*ValueGroup) []ValueGroup {
+	result := make([]ValueGroup, 0, len(groups))
+	for _, g := range groups {
+		g.Value = roundCents(g.Value)
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Value != result[j].Value {
+			return result[i].Value > result[j].Value
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// roundCents rounds an amount to two decimal places
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// OwnershipFilter selects books by ownership, owned format and shelf
+// location. Empty fields match everything.
+type OwnershipFilter struct {
+	Ownership string
+	Format    string
+	Location  string // case-insensitive substring of the shelf location
+}
+
+// IsZero reports whether the filter matches every book
+func (f OwnershipFilter) IsZero() bool {
+	return f.Ownership == "" && f.Format == "" && f.Location == ""
+}
+
+// FilterByOwnership returns the books matching the filter
+func FilterByOwnership(books []Book, f OwnershipFilter) []Book {
+	if f.IsZero() {
+		return books
+	}
+	ownership := NormalizeOwnership(f.Ownership)
+	format := NormalizeFormat(f.Format)
+	location := strings.ToLower(strings.TrimSpace(f.Location))
+
+	var result []Book
+	for _, book := range books {
+		if f.Ownership != "" && book.GetOwnership() != ownership {
+			continue
+		}
+		if f.Format != "" && NormalizeFormat(book.OwnedFormat) != format {
+			continue
+		}
+		if location != "" && !strings.Contains(strings.ToLower(book.ShelfLocation), location) {
+			continue
+		}
+		result = append(result, book)
+	}
+	return result
+}
@@ -0,0 +1,89 @@
+package books
+
+import "testing"
+
+// TestNormalizeOwnership verifies common spellings map to the four states
+func TestNormalizeOwnership(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Owned", OwnershipOwned},
+		{"own", OwnershipOwned},
+		{"Borrowed", OwnershipBorrowed},
+		{"Library Loan", OwnershipLibrary},
+		{"wish list", OwnershipWishlist},
+		{"stolen", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeOwnership(tt.input); got != tt.want {
+			t.Errorf("NormalizeOwnership(%q): got %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestGetOwnershipOwnedCopies verifies Goodreads owned copies count as owned
+func TestGetOwnershipOwnedCopies(t *testing.T) {
+	book := Book{OwnedCopies: float64(1)}
+	if got := book.GetOwnership(); got != OwnershipOwned {
+		t.Errorf("Expected owned, got %q", got)
+	}
+}
+
+// TestCalculateLibraryValue verifies totals and groupings of owned books
+func TestCalculateLibraryValue(t *testing.T) {
+	// Given
+	books := []Book{
+		{Title: "A", Ownership: "owned", OwnedFormat: "print", PurchasePrice: 20.5, ShelfLocation: "Study"},
+		{Title: "B", Ownership: "owned", OwnedFormat: "print", PurchasePrice: "$9.99", ShelfLocation: "Study"},
+		{Title: "C", Ownership: "owned", OwnedFormat: "ebook", PurchasePrice: 4.0},
+		{Title: "D", Ownership: "owned"},
+		{Title: "E", Ownership: "wishlist", PurchasePrice: 30.0},
+		{Title: "F", Ownership: "library"},
+		{Title: "G"},
+	}
+
+	// When
+	value := CalculateLibraryValue(books)
+
+	// Then
+	if value.Owned != 4 || value.Priced != 3 {
+		t.Errorf("Expected 4 owned and 3 priced, got %d and %d", value.Owned, value.Priced)
+	}
+	if value.TotalValue != 34.49 {
+		t.Errorf("TotalValue: got %v, want 34.49", value.TotalValue)
+	}
+	if value.AvgPrice != 11.5 {
+		t.Errorf("AvgPrice: got %v, want 11.5", value.AvgPrice)
+	}
+	if len(value.ByFormat) != 3 || value.ByFormat[0].Name != "print" || value.ByFormat[0].Value != 30.49 {
+		t.Errorf("Unexpected format groups: %+v", value.ByFormat)
+	}
+	if value.ByLocation[0].Name != "Study" || value.ByLocation[0].Count != 2 {
+		t.Errorf("Unexpected location groups: %+v", value.ByLocation)
+	}
+	if value.Ownership[OwnershipWishlist] != 1 || value.Ownership[OwnershipLibrary] != 1 {
+		t.Errorf("Unexpected ownership counts: %v", value.Ownership)
+	}
+}
+
+// TestFilterByOwnership verifies ownership, format and location filters combine
+func TestFilterByOwnership(t *testing.T) {
+	books := []Book{
+		{Title: "A", Ownership: "owned", OwnedFormat: "print", ShelfLocation: "Study, shelf 3"},
+		{Title: "B", Ownership: "owned", OwnedFormat: "ebook"},
+		{Title: "C", Ownership: "borrowed", ShelfLocation: "Study"},
+	}
+
+	if got := FilterByOwnership(books, OwnershipFilter{Ownership: "owned"}); len(got) != 2 {
+		t.Errorf("Expected 2 owned, got %d", len(got))
+	}
+	got := FilterByOwnership(books, OwnershipFilter{Ownership: "owned", Location: "study"})
+	if len(got) != 1 || got[0].GetTitle() != "A" {
+		t.Errorf("Expected A, got %+v", got)
+	}
+	if got := FilterByOwnership(books, OwnershipFilter{Format: "Kindle Edition"}); len(got) != 1 {
+		t.Errorf("Expected 1 ebook, got %d", len(got))
+	}
+}
@@ -0,0 +1,198 @@
+package books
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ShelfToRead is the shelf of books we want to read
+const ShelfToRead = "to-read"
+
+// PickWeights sets how strongly each rule favours a to-read book. A weight
+// of zero turns the rule off.
+type PickWeights struct {
+	Age    float64 // long-waiting books, by DateAdded
+	Pages  float64 // books within the requested page range
+	Author float64 // authors we haven't read recently, or at all
+	Series float64 // the next unread book of a series we've started
+	Tags   float64 // books carrying the requested tags
+}
+
+// DefaultPickWeights gives every rule equal say
+var DefaultPickWeights = PickWeights{Age: 1, Pages: 1, Author: 1, Series: 1, Tags: 1}
+
+// PickOptions configures a TBR pick
+type PickOptions struct {
+	Weights      PickWeights
+	MinPages     int      // 0 for no lower bound
+	MaxPages     int      // 0 for no upper bound
+	Tags         []string // favour books with any of these tags
+	RecentMonths int      // an author read within this many months is "recent"
+	Count        int      // how many candidates to return
+	Seed         int64    // the same seed and books give the same picks
+	Now          time.Time
+}
+
+// Pick is a to-read book chosen by PickTBR and why it scored as it did
+type Pick struct {
+	Book    Book
+	Score   float64
+	Reasons []string
+}
+
+// ageForFullScore is how long a book must wait to earn the whole age weight
+const ageForFullScore = 5 * 365 * 24 * time.Hour
+
+// parseLooseDate parses a YYYY/MM/DD date, treating a missing month or day
+// as the first
+func parseLooseDate(s string) (time.Time, bool) {
+	d, err := ParseDate(s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	month, day := d.Month, d.Day
+	if month == 0 {
+		month = 1
+	}
+	if day == 0 {
+		day = 1
+	}
+	return time.Date(d.Year, time.Month(month), day, 0, 0, 0, 0, time.UTC), true
+}
+
+// PickTBR scores every book on the to-read shelf and draws opts.Count of
+// them at random, each draw weighted by score, so higher-scoring books are
+// likelier but never certain. Every book scores at least 1.
+func PickTBR(books []Book, opts PickOptions) []Pick {
+	if opts.Count <= 0 {
+		opts.Count = 5
+	}
+	if opts.RecentMonths <= 0 {
+		opts.RecentMonths = 12
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	// When we last finished a book by each author
+	lastRead := make(map[string]time.Time)
+	authorKeys := bookAuthorKeys(books)
+	for _, book := range books {
+		key := authorKeys[book.Author]
+		for _, date := range book.ReadDates() {
+			if t, ok := parseLooseDate(date); ok && t.After(lastRead[key]) {
+				lastRead[key] = t
+			}
+		}
+	}
+
+	// Next unread entries of series we've started
+	nextUp := make(map[string]string)
+	for _, s := range GroupBySeries(books) {
+		if s.Read > 0 && s.NextUp != nil {
+			nextUp[pickKey(s.NextUp.Book)] = s.Name
+		}
+	}
+
+	recentCutoff := opts.Now.AddDate(0, -opts.RecentMonths, 0)
+	var candidates []Pick
+	for _, book := range books {
+		if NormalizeShelf(book.Shelf) != ShelfToRead {
+			continue
+		}
+		p := Pick{Book: book, Score: 1}
+
+		if w := opts.Weights.Age; w > 0 {
+			if added, ok := parseLooseDate(book.GetDateAdded()); ok && added.Before(opts.Now) {
+				waited := opts.Now.Sub(added)
+				factor := float64(waited) / float64(ageForFullScore)
+				if factor > 1 {
+					factor = 1
+				}
+				p.Score += w * factor
+				if years := int(waited.Hours() / 24 / 365); years >= 1 {
+					p.Reasons = append(p.Reasons, fmt.Sprintf("On the TBR for %d+ years (added %s)", years, book.GetDateAdded()))
+				}
+			}
+		}
+
+		if w := opts.Weights.Pages; w > 0 && (opts.MinPages > 0 || opts.MaxPages > 0) {
+			pages := book.GetPages()
+			if pages > 0 && pages >= opts.MinPages && (opts.MaxPages == 0 || pages <= opts.MaxPages) {
+				p.Score += w
+				p.Reasons = append(p.Reasons, fmt.Sprintf("%d pages fits the page range", pages))
+			}
+		}
+
+		if w := opts.Weights.Author; w > 0 {
+			last, read := lastRead[authorKeys[book.Author]]
+			switch {
+			case !read:
+				p.Score += w / 2
+				p.Reasons = append(p.Reasons, "New author: "+book.Author)
+			case last.Before(recentCutoff):
+				p.Score += w
+				p.Reasons = append(p.Reasons, fmt.Sprintf("Haven't read %s since %d", book.Author, last.Year()))
+			}
+		}
+
+		if w := opts.Weights.Series; w > 0 {
+			if series, ok := nextUp[pickKey(book)]; ok {
+				p.Score += w
+				p.Reasons = append(p.Reasons, "Next up in "+series)
+			}
+		}
+
+		if w := opts.Weights.Tags; w > 0 && len(opts.Tags) > 0 {
+			var matched []string
+			for _, tag := range opts.Tags {
+				if book.HasTag(tag) {
+					matched = append(matched, NormalizeShelf(tag))
+				}
+			}
+			if len(matched) > 0 {
+				p.Score += w * float64(len(matched)) / float64(len(opts.Tags))
+				p.Reasons = append(p.Reasons, "Tagged "+strings.Join(matched, ", "))
+			}
+		}
+
+		candidates = append(candidates, p)
+	}
+
+	// Fix the order before drawing so the seed alone decides the picks
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return pickKey(candidates[i].Book) < pickKey(candidates[j].Book)
+	})
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	var picks []Pick
+	for len(picks) < opts.Count && len(candidates) > 0 {
+		total := 0.0
+		for _, c := range candidates {
+			total += c.Score
+		}
+		target := rng.Float64() * total
+		i := 0
+		for ; i < len(candidates)-1; i++ {
+			target -= candidates[i].Score
+			if target < 0 {
+				break
+			}
+		}
+		picks = append(picks, candidates[i])
+		candidates = append(candidates[:i], candidates[i+1:]...)
+	}
+	return picks
+}
+
+// pickKey identifies a book by ID, or by title and author for books loaded
+// from JSON
+func pickKey(b Book) string {
+	if b.ID != 0 {
+		return fmt.Sprintf("%020d", b.ID)
+	}
+	return strings.ToLower(b.GetTitle()) + "\x00" + NormalizeAuthor(b.Author)
+}
@@ -0,0 +1,97 @@
+package books
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pickTestBooks returns a small library with a to-read backlog
+func pickTestBooks() []Book {
+	return []Book{
+		{Title: "The Fifth Season (The Broken Earth, #1)", Author: "N. K. Jemisin", Shelf: "read", DateRead: "2025/02/01"},
+		{Title: "The Obelisk Gate (The Broken Earth, #2)", Author: "N. K. Jemisin", Shelf: "to-read", DateAdded: "2024/02/02", Pages: 410},
+		{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read", DateRead: "2019/05/01"},
+		{Title: "Wild Seed", Author: "Octavia Butler", Shelf: "to-read", DateAdded: "2016/01/01", Pages: 300},
+		{Title: "Piranesi", Author: "Susanna Clarke", Shelf: "to-read", DateAdded: "2025/06/01", Pages: 272, Bookshelves: "to-read, fantasy"},
+		{Title: "Dune", Author: "Frank Herbert", Shelf: "read", DateRead: "2025/01/01"},
+	}
+}
+
+// TestPickTBRReasons verifies each rule contributes a reason
+func TestPickTBRReasons(t *testing.T) {
+	// Given
+	opts := PickOptions{
+		Weights:  DefaultPickWeights,
+		MinPages: 250,
+		MaxPages: 350,
+		Tags:     []string{"fantasy"},
+		Count:    3,
+		Seed:     1,
+		Now:      time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	// When
+	picks := PickTBR(pickTestBooks(), opts)
+
+	// Then
+	if len(picks) != 3 {
+		t.Fatalf("Expected all 3 to-read books, got %d", len(picks))
+	}
+	reasons := make(map[string]string)
+	for _, p := range picks {
+		reasons[p.Book.GetTitle()] = strings.Join(p.Reasons, "; ")
+	}
+	wild := reasons["Wild Seed"]
+	if !strings.Contains(wild, "On the TBR for 9+ years") || !strings.Contains(wild, "Haven't read Octavia Butler since 2019") ||
+		!strings.Contains(wild, "300 pages") {
+		t.Errorf("Unexpected Wild Seed reasons: %q", wild)
+	}
+	if !strings.Contains(reasons["The Obelisk Gate (The Broken Earth, #2)"], "Next up in The Broken Earth") {
+		t.Errorf("Expected series next-up reason, got %q", reasons["The Obelisk Gate (The Broken Earth, #2)"])
+	}
+	if strings.Contains(reasons["The Obelisk Gate (The Broken Earth, #2)"], "Haven't read") {
+		t.Errorf("Expected Jemisin to count as read recently")
+	}
+	piranesi := reasons["Piranesi"]
+	if !strings.Contains(piranesi, "Tagged fantasy") || !strings.Contains(piranesi, "New author") {
+		t.Errorf("Unexpected Piranesi reasons: %q", piranesi)
+	}
+}
+
+// TestPickTBRDeterministic verifies the same seed gives the same picks
+func TestPickTBRDeterministic(t *testing.T) {
+	titles := func(seed int64) []string {
+		var result []string
+		for _, p := range PickTBR(pickTestBooks(), PickOptions{Weights: DefaultPickWeights, Count: 2, Seed: seed}) {
+			result = append(result, p.Book.GetTitle())
+		}
+		return result
+	}
+
+	first := titles(42)
+	for i := 0; i < 5; i++ {
+		if got := titles(42); !reflect.DeepEqual(got, first) {
+			t.Fatalf("Seed 42 gave %v then %v", first, got)
+		}
+	}
+	if len(first) != 2 {
+		t.Errorf("Expected 2 picks, got %d", len(first))
+	}
+}
+
+// TestPickTBRWeightsFavour verifies a heavy weight dominates the draw
+func TestPickTBRWeightsFavour(t *testing.T) {
+	opts := PickOptions{Weights: PickWeights{Tags: 1000}, Tags: []string{"fantasy"}, Count: 1}
+	hits := 0
+	for seed := int64(0); seed < 20; seed++ {
+		opts.Seed = seed
+		if picks := PickTBR(pickTestBooks(), opts); picks[0].Book.GetTitle() == "Piranesi" {
+			hits++
+		}
+	}
+	if hits < 18 {
+		t.Errorf("Expected the tagged book in nearly every pick, got %d of 20", hits)
+	}
+}
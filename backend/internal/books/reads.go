@@ -0,0 +1,90 @@
+package books
+
+import (
+	"sort"
+	"time"
+)
+
+// Read is one reading of a book. A book re-read several times has several.
+type Read struct {
+	DateStarted  string
+	DateFinished string
+	Format       string
+	Notes        string
+}
+
+// FinishedReads returns the reads of the book that count as books read in
+// their years: every read with a finish date of a book on the read shelf,
+// or of one we abandoned after finishing it before. This is the rule year
+// lists, summaries and FilterByYear's read entries all share.
+func (b *Book) FinishedReads() []Read {
+	if shelf := NormalizeShelf(b.Shelf); shelf != "read" && shelf != ShelfDNF {
+		return nil
+	}
+	var finished []Read
+	for _, r := range b.AllReads() {
+		if r.DateFinished != "" {
+			finished = append(finished, r)
+		}
+	}
+	return finished
+}
+
+// ReadDates returns the finish date of every finished read of the book
+// (see FinishedReads)
+func (b *Book) ReadDates() []string {
+	var dates []string
+	for _, r := range b.FinishedReads() {
+		dates = append(dates, r.DateFinished)
+	}
+	return dates
+}
+
+// ReadCount returns how many times the book has been read, counting
+// undated reads and the Goodreads "Read Count" column
+func (b *Book) ReadCount() int {
+	if len(b.Reads) > 0 {
+		return len(b.Reads)
+	}
+	if n := toCount(b.GoodreadsReadCount); n > 0 {
+		return n
+	}
+	if b.DateRead != "" {
+		return 1
+	}
+	return 0
+}
+
+// FinishedRead is one finished read of a book
+type FinishedRead struct {
+	Book     Book
+	Finished time.Time
+}
+
+// RecentlyFinished returns the latest finished reads, newest first, up to
+// limit. A non-empty shelf keeps books on that shelf or carrying it as a
+// tag. Reads with a missing month or day count from the first.
+func RecentlyFinished(books []Book, shelf string, limit int) []FinishedRead {
+	shelf = NormalizeShelf(shelf)
+	var reads []FinishedRead
+	for _, book := range books {
+		if shelf != "" && NormalizeShelf(book.Shelf) != shelf && !book.HasTag(shelf) {
+			continue
+		}
+		for _, date := range book.ReadDates() {
+			if t, ok := parseLooseDate(date); ok {
+				read := book
+				read.DateRead = date
+				reads = append(reads, FinishedRead{Book: read, Finished: t})
+			}
+		}
+	}
+
+	sort.SliceStable(reads, func(i, j int) bool {
+		return reads[i].Finished.After(reads[j].Finished)
+	})
+	if limit > 0 && len(reads) > limit {
+		reads = reads[:limit]
+	}
+	return reads
+}
@@ -0,0 +1,32 @@
+package books
+
+import "testing"
+
+// TestRecentlyFinished verifies reads are newest first, filtered by shelf
+func TestRecentlyFinished(t *testing.T) {
+	// Given
+	books := []Book{
+		{Title: "Old", Shelf: "read", DateRead: "2023/04/01"},
+		{Title: "Reread", Shelf: "read", Bookshelves: "read, fantasy", Reads: []Read{{DateFinished: "2020/01/01"}, {DateFinished: "2025/06/10"}}},
+		{Title: "Month Only", Shelf: "read", Bookshelves: "fantasy", DateRead: "2025/03"},
+		{Title: "Waiting", Shelf: "to-read"},
+	}
+
+	// When
+	all := RecentlyFinished(books, "", 3)
+	fantasy := RecentlyFinished(books, "Fantasy", 0)
+
+	// Then
+	if len(all) != 3 {
+		t.Fatalf("Expected 3 reads, got %d", len(all))
+	}
+	if all[0].Book.GetTitle() != "Reread" || all[0].Book.DateRead != "2025/06/10" {
+		t.Errorf("Expected the re-read first, got %s on %s", all[0].Book.GetTitle(), all[0].Book.DateRead)
+	}
+	if all[1].Book.GetTitle() != "Month Only" || all[1].Finished.Day() != 1 {
+		t.Errorf("Expected the month-only read second, got %s on %v", all[1].Book.GetTitle(), all[1].Finished)
+	}
+	if len(fantasy) != 3 {
+		t.Errorf("Expected 3 fantasy reads, got %d", len(fantasy))
+	}
+}
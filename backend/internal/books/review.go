@@ -0,0 +1,142 @@
+package books
+
+import (
+	"sort"
+	"time"
+)
+
+// ShelfCount is how many books read in a year carried a shelf or tag
+type ShelfCount struct {
+	Shelf string
+	Count int
+}
+
+// YearReview gathers a year of reading into a recap
+type YearReview struct {
+	Year             int
+	Books            int
+	Pages            int
+	AvgPages         float64 // over books with a page count
+	GoalTarget       int     // 0 when no goal was set
+	GoalStatus       string  // met, missed or in progress; "" without a goal
+	First            *Book   // earliest finished, by date read
+	Last             *Book   // latest finished
+	Longest          *Book
+	Shortest         *Book
+	TopRated         []Book  // up to five rated books, highest first
+	AvgRating        float64 // over rated books
+	BusiestMonth     *MonthTotal
+	Months           []MonthTotal // every month of the year, January first
+	Authors          int          // distinct authors read
+	TopAuthors       []AuthorCount
+	NewAuthors       int
+	ReturningAuthors int
+	Shelves          []ShelfCount // tags on the books read, most common first
+}
+
+// CalculateYearReview builds the recap for year from every book, counting
+// each finished read on the read shelf. goal is the year's book target, 0
+// for none.
+func CalculateYearReview(books []Book, year, goal int, now time.Time) YearReview {
+	filtered, _ := FilterByYear(books, year)
+	read := FilterByShelf(filtered, "read")
+	stats := CalculateStatisticsAt(read, year, now)
+
+	review := YearReview{
+		Year:       year,
+		Books:      stats.TotalBooks,
+		Pages:      stats.TotalPages,
+		GoalTarget: goal,
+		Longest:    stats.Longest,
+		Shortest:   stats.Shortest,
+		TopAuthors: stats.TopAuthors,
+	}
+	review.NewAuthors, review.ReturningAuthors = CalculateAuthorNovelty(books, year)
+
+	if goal > 0 {
+		switch {
+		case review.Books >= goal:
+			review.GoalStatus = GoalMet
+		case year >= now.Year():
+			review.GoalStatus = GoalInProgress
+		default:
+			review.GoalStatus = GoalMissed
+		}
+	}
+
+	for m := 1; m <= 12; m++ {
+		review.Months = append(review.Months, MonthTotal{Year: year, Month: m})
+	}
+
+	var firstAt, lastAt time.Time
+	authors := make(map[string]bool)
+	shelves := make(map[string]int)
+	paged, rated, ratingSum := 0, 0, 0.0
+	authorKeys := bookAuthorKeys(read)
+	for i := range read {
+		book := &read[i]
+		pages := book.GetPages()
+		if pages > 0 {
+			paged++
+		}
+		if r := book.GetRating(); r > 0 {
+			rated++
+			ratingSum += r
+			review.TopRated = append(review.TopRated, *book)
+		}
+		if key := authorKeys[book.Author]; key != "" {
+			authors[key] = true
+		}
+		for _, tag := range book.GetTags() {
+			shelves[tag]++
+		}
+
+		if t, ok := parseLooseDate(book.DateRead); ok {
+			if review.First == nil || t.Before(firstAt) {
+				review.First, firstAt = book, t
+			}
+			if review.Last == nil || !t.Before(lastAt) {
+				review.Last, lastAt = book, t
+			}
+		}
+		if d, err := ParseDate(book.DateRead); err == nil && d.Month >= 1 && d.Month <= 12 {
+			review.Months[d.Month-1].Books++
+			if pages > 0 {
+				review.Months[d.Month-1].Pages += pages
+			}
+		}
+	}
+
+	if paged > 0 {
+		review.AvgPages = roundCents(float64(review.Pages) / float64(paged))
+	}
+	if rated > 0 {
+		review.AvgRating = roundCents(ratingSum / float64(rated))
+	}
+	review.Authors = len(authors)
+
+	sort.SliceStable(review.TopRated, func(i, j int) bool {
+		return review.TopRated[i].GetRating() > review.TopRated[j].GetRating()
+	})
+	if len(review.TopRated) > 5 {
+		review.TopRated = review.TopRated[:5]
+	}
+
+	for i := range review.Months {
+		m := &review.Months[i]
+		if m.Books > 0 && (review.BusiestMonth == nil || monthRanksBelow(*review.BusiestMonth, *m)) {
+			review.BusiestMonth = m
+		}
+	}
+
+	for shelf, count := range shelves {
+		review.Shelves = append(review.Shelves, ShelfCount{Shelf: shelf, Count: count})
+	}
+	sort.Slice(review.Shelves, func(i, j int) bool {
+		if review.Shelves[i].Count != review.Shelves[j].Count {
+			return review.Shelves[i].Count > review.Shelves[j].Count
+		}
+		return review.Shelves[i].Shelf < review.Shelves[j].Shelf
+	})
+	return review
+}
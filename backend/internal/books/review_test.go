@@ -0,0 +1,63 @@
+package books
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCalculateYearReview verifies the recap's highlights, months and shelves
+func TestCalculateYearReview(t *testing.T) {
+	// Given
+	books := []Book{
+		{Title: "Opener", Author: "A", Shelf: "read", DateRead: "2025/01/03", Pages: 250, Rating: 4, Bookshelves: "read, sci-fi"},
+		{Title: "Epic", Author: "B", Shelf: "read", DateRead: "2025/03/12", Pages: 900, Rating: 5, Bookshelves: "read, fantasy, favorites"},
+		{Title: "Slim", Author: "B", Shelf: "read", DateRead: "2025/03/20", Pages: 90, Rating: "3", Bookshelves: "read, fantasy"},
+		{Title: "Closer", Author: "C", Shelf: "read", DateRead: "2025/11/30", Pages: 310},
+		{Title: "Old Favourite", Author: "A", Shelf: "read", DateRead: "2023/06/01", Pages: 200},
+		{Title: "Abandoned", Author: "D", Shelf: "dnf", DNFDate: "2025/02/01", Pages: 400},
+	}
+	now := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	// When
+	review := CalculateYearReview(books, 2025, 5, now)
+
+	// Then
+	if review.Books != 4 || review.Pages != 1550 {
+		t.Errorf("Totals: got %d books, %d pages, want 4 and 1550", review.Books, review.Pages)
+	}
+	if review.GoalTarget != 5 || review.GoalStatus != GoalMissed {
+		t.Errorf("Goal: got %d %q, want 5 missed", review.GoalTarget, review.GoalStatus)
+	}
+	if review.First == nil || review.First.GetTitle() != "Opener" || review.Last == nil || review.Last.GetTitle() != "Closer" {
+		t.Errorf("First/last: got %v / %v", review.First, review.Last)
+	}
+	if review.Longest.GetTitle() != "Epic" || review.Shortest.GetTitle() != "Slim" {
+		t.Errorf("Longest/shortest: got %s / %s", review.Longest.GetTitle(), review.Shortest.GetTitle())
+	}
+	if len(review.TopRated) != 3 || review.TopRated[0].GetTitle() != "Epic" || review.AvgRating != 4 {
+		t.Errorf("Top rated: got %d books, avg %.2f", len(review.TopRated), review.AvgRating)
+	}
+	if review.BusiestMonth == nil || review.BusiestMonth.Month != 3 || review.BusiestMonth.Books != 2 {
+		t.Errorf("Busiest month: got %+v, want March with 2", review.BusiestMonth)
+	}
+	if len(review.Months) != 12 || review.Months[10].Books != 1 {
+		t.Errorf("Months: got %+v", review.Months)
+	}
+	if review.Authors != 3 || review.NewAuthors != 2 || review.ReturningAuthors != 1 {
+		t.Errorf("Authors: got %d (%d new, %d returning), want 3 (2, 1)", review.Authors, review.NewAuthors, review.ReturningAuthors)
+	}
+	if len(review.Shelves) != 3 || review.Shelves[0] != (ShelfCount{Shelf: "fantasy", Count: 2}) {
+		t.Errorf("Shelves: got %+v", review.Shelves)
+	}
+}
+
+// TestCalculateYearReviewEmpty verifies a year with no reading
+func TestCalculateYearReviewEmpty(t *testing.T) {
+	// When
+	review := CalculateYearReview(nil, 2025, 0, time.Now())
+
+	// Then
+	if review.Books != 0 || review.First != nil || review.BusiestMonth != nil || review.GoalStatus != "" {
+		t.Errorf("Expected an empty review, got %+v", review)
+	}
+}
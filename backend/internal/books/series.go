@@ -0,0 +1,121 @@
+package books
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SeriesInfo is a series membership parsed from a title
+type SeriesInfo struct {
+	Name     string
+	Position float64 // 0 when the title gives no number
+}
+
+// seriesSuffix matches a trailing "(Series, #2)" or "(A, #1; B, #3)" group
+var seriesSuffix = regexp.MustCompile(`^(.*?)\s*\(([^()]*#[^()]*)\)\s*$`)
+
+// seriesPart matches one "Series, #1.5" entry, allowing omnibus ranges like "#1-3"
+var seriesPart = regexp.MustCompile(`^(.*?),?\s*#\s*(\d+(?:\.\d+)?)(?:\s*-\s*\d+(?:\.\d+)?)?$`)
+
+// ParseSeries splits a Goodreads-style title such as
+// "Adulthood Rites (Xenogenesis, #2)" into its base title and series entries.
+// Titles without series information are returned unchanged with no entries.
+func ParseSeries(title string) (string, []SeriesInfo) {
+	m := seriesSuffix.FindStringSubmatch(strings.TrimSpace(title))
+	if m == nil {
+		return title, nil
+	}
+
+	var series []SeriesInfo
+	for _, part := range strings.Split(m[2], ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if pm := seriesPart.FindStringSubmatch(part); pm != nil {
+			pos, _ := strconv.ParseFloat(pm[2], 64)
+			name := strings.TrimSpace(pm[1])
+			if name != "" {
+				series = append(series, SeriesInfo{Name: name, Position: pos})
+			}
+			continue
+		}
+		series = append(series, SeriesInfo{Name: part})
+	}
+
+	if len(series) == 0 {
+		return title, nil
+	}
+	return m[1], series
+}
+
+// GetSeries returns the series the book belongs to, preferring the title
+// and falling back to the stored series for titles without one
+func (b *Book) GetSeries() []SeriesInfo {
+	if _, series := ParseSeries(b.GetTitle()); len(series) > 0 {
+		return series
+	}
+	if b.Series != "" {
+		return []SeriesInfo{{Name: b.Series, Position: b.SeriesPosition}}
+	}
+	return nil
+}
+
+// SeriesEntry is a book's place within a series
+type SeriesEntry struct {
+	Book     Book
+	Position float64
+}
+
+// Series groups the books of one series in reading order
+type Series struct {
+	Name    string
+	Entries []SeriesEntry
+	Read    int
+	Unread  int
+	NextUp  *SeriesEntry // first unread entry in order, nil when all are read
+}
+
+// GroupBySeries collects books into series sorted by name, with entries
+// ordered by position. A book in several series appears in each of them.
+func GroupBySeries(books []Book) []Series {
+	byName := make(map[string]*Series)
+	var names []string
+
+	for _, book := range books {
+		for _, info := range book.GetSeries() {
+			key := strings.ToLower(info.Name)
+			s, ok := byName[key]
+			if !ok {
+				s = &Series{Name: info.Name}
+				byName[key] = s
+				names = append(names, key)
+			}
+			s.Entries = append(s.Entries, SeriesEntry{Book: book, Position: info.Position})
+		}
+	}
+
+	sort.Strings(names)
+	result := make([]Series, 0, len(names))
+	for _, key := range names {
+		s := byName[key]
+		sort.SliceStable(s.Entries, func(i, j int) bool {
+			return s.Entries[i].Position < s.Entries[j].Position
+		})
+		for i := range s.Entries {
+			if s.Entries[i].Book.Shelf == "read" {
+				s.Read++
+				continue
+			}
+			s.Unread++
+			if s.NextUp == nil {
+				entry := s.Entries[i]
+				s.NextUp = &entry
+			}
+		}
+		result = append(result, *s)
+	}
+	return result
+}
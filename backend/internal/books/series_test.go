@@ -0,0 +1,124 @@
+package books
+
+import (
+	"testing"
+)
+
+// TestParseSeries verifies extracting series name and position from titles
+func TestParseSeries(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantTitle string
+		want      []SeriesInfo
+	}{
+		{
+			name:      "numbered series",
+			input:     "Adulthood Rites (Xenogenesis, #2)",
+			wantTitle: "Adulthood Rites",
+			want:      []SeriesInfo{{Name: "Xenogenesis", Position: 2}},
+		},
+		{
+			name:      "novella position",
+			input:     "Tales of the Celestial Kingdom (The Celestial Kingdom, #2.5)",
+			wantTitle: "Tales of the Celestial Kingdom",
+			want:      []SeriesInfo{{Name: "The Celestial Kingdom", Position: 2.5}},
+		},
+		{
+			name:      "multiple series",
+			input:     "The Testaments (The Handmaid's Tale, #2; Gilead, #1)",
+			wantTitle: "The Testaments",
+			want: []SeriesInfo{
+				{Name: "The Handmaid's Tale", Position: 2},
+				{Name: "Gilead", Position: 1},
+			},
+		},
+		{
+			name:      "omnibus range",
+			input:     "The Expanse Box Set (The Expanse, #1-3)",
+			wantTitle: "The Expanse Box Set",
+			want:      []SeriesInfo{{Name: "The Expanse", Position: 1}},
+		},
+		{
+			name:      "no series",
+			input:     "Co-Intelligence",
+			wantTitle: "Co-Intelligence",
+		},
+		{
+			name:      "parenthetical subtitle",
+			input:     "Kindred (Graphic Novel Adaptation)",
+			wantTitle: "Kindred (Graphic Novel Adaptation)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, series := ParseSeries(tt.input)
+
+			if title != tt.wantTitle {
+				t.Errorf("Title: got %q, want %q", title, tt.wantTitle)
+			}
+			if len(series) != len(tt.want) {
+				t.Fatalf("Series: got %v, want %v", series, tt.want)
+			}
+			for i := range tt.want {
+				if series[i] != tt.want[i] {
+					t.Errorf("Series[%d]: got %+v, want %+v", i, series[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestGroupBySeries verifies grouping, ordering and next-up selection
+func TestGroupBySeries(t *testing.T) {
+	// Given books from a series read partway through, plus a standalone
+	books := []Book{
+		{Title: "Imago (Xenogenesis, #3)", Author: "Octavia E. Butler", Shelf: "to-read"},
+		{Title: "Dawn (Xenogenesis, #1)", Author: "Octavia E. Butler", Shelf: "read"},
+		{Title: "Adulthood Rites (Xenogenesis, #2)", Author: "Octavia E. Butler", Shelf: "currently-reading"},
+		{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"},
+	}
+
+	// When grouping by series
+	series := GroupBySeries(books)
+
+	// Then there should be a single series
+	if len(series) != 1 {
+		t.Fatalf("Expected 1 series, got %d", len(series))
+	}
+	s := series[0]
+
+	// And entries should be in position order
+	for i, want := range []float64{1, 2, 3} {
+		if s.Entries[i].Position != want {
+			t.Errorf("Entry %d position: got %v, want %v", i, s.Entries[i].Position, want)
+		}
+	}
+
+	// And read/unread counts should reflect shelves
+	if s.Read != 1 || s.Unread != 2 {
+		t.Errorf("Read/Unread: got %d/%d, want 1/2", s.Read, s.Unread)
+	}
+
+	// And next up should be the first unread book
+	if s.NextUp == nil || s.NextUp.Position != 2 {
+		t.Errorf("NextUp: got %+v, want position 2", s.NextUp)
+	}
+}
+
+// TestGroupBySeriesStoredSeries verifies the stored series is used when the title has none
+func TestGroupBySeriesStoredSeries(t *testing.T) {
+	books := []Book{
+		{Title: "Wayfarers Book One", Series: "Wayfarers", SeriesPosition: 1, Shelf: "read"},
+	}
+
+	series := GroupBySeries(books)
+
+	if len(series) != 1 || series[0].Name != "Wayfarers" {
+		t.Fatalf("Expected Wayfarers series, got %+v", series)
+	}
+	if series[0].NextUp != nil {
+		t.Error("Expected no next-up book when the series is fully read")
+	}
+}
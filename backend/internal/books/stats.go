@@ -1,11 +1,54 @@
 package books
 
+import (
+	"sort"
+	"time"
+)
+
 // Statistics represents reading statistics for a year
 type Statistics struct {
 	Year            int
 	TotalBooks      int
 	TotalPages      int
 	AveragePerMonth float64
+	MonthsElapsed   int // months of the year counted in AveragePerMonth
+
+	MedianPages   float64 // over books with a page count
+	Longest       *Book
+	Shortest      *Book
+	PageHistogram []PageBucket
+	TopAuthors    []AuthorCount // up to five, most books first
+	Decades       []DecadeCount // by original publication year, oldest first
+}
+
+// PageBucket counts books within a range of page counts
+type PageBucket struct {
+	Label string
+	Min   int
+	Max   int // 0 for no upper bound
+	Count int
+}
+
+// AuthorCount is how much of one author was read
+type AuthorCount struct {
+	Author string
+	Books  int
+	Pages  int
+}
+
+// DecadeCount counts books first published in a decade
+type DecadeCount struct {
+	Decade int // e.g. 1990
+	Count  int
+}
+
+// pageBuckets are the histogram ranges for book length
+var pageBuckets = []PageBucket{
+	{Label: "under 200", Min: 1, Max: 199},
+	{Label: "200-299", Min: 200, Max: 299},
+	{Label: "300-399", Min: 300, Max: 399},
+	{Label: "400-499", Min: 400, Max: 499},
+	{Label: "500+", Min: 500},
 }
 
 // MonthlyCount represents book count for a month
@@ -17,27 +60,160 @@ type MonthlyCount struct {
 
 // CalculateStatistics calculates reading statistics for a year
 func CalculateStatistics(books []Book, year int) Statistics {
+	return CalculateStatisticsAt(books, year, time.Now())
+}
+
+// CalculateStatisticsAt calculates reading statistics for a year as of now.
+// For the current year the monthly average covers only the months so far.
+func CalculateStatisticsAt(books []Book, year int, now time.Time) Statistics {
 	stats := Statistics{
-		Year:       year,
-		TotalBooks: len(books),
+		Year:          year,
+		TotalBooks:    len(books),
+		MonthsElapsed: 12,
+	}
+	if year == now.Year() {
+		stats.MonthsElapsed = int(now.Month())
 	}
 	
 	// Calculate total pages (exclude zero-page books)
-	for _, book := range books {
+	var lengths []int
+	histogram := append([]PageBucket(nil), pageBuckets...)
+	for i, book := range books {
 		pages := book.GetPages()
-		if pages > 0 {
-			stats.TotalPages += pages
+		if pages <= 0 {
+			continue
+		}
+		stats.TotalPages += pages
+		lengths = append(lengths, pages)
+		if stats.Longest == nil || pages > stats.Longest.GetPages() {
+			stats.Longest = &books[i]
+		}
+		if stats.Shortest == nil || pages < stats.Shortest.GetPages() {
+			stats.Shortest = &books[i]
+		}
+		for j := range histogram {
+			if pages >= histogram[j].Min && (histogram[j].Max == 0 || pages <= histogram[j].Max) {
+				histogram[j].Count++
+				break
+			}
+		}
+	}
+	stats.PageHistogram = histogram
+	
+	if len(lengths) > 0 {
+		sort.Ints(lengths)
+		mid := len(lengths) / 2
+		if len(lengths)%2 == 0 {
+			stats.MedianPages = float64(lengths[mid-1]+lengths[mid]) / 2
+		} else {
+			stats.MedianPages = float64(lengths[mid])
 		}
 	}
 	
 	// Calculate average per month
 	if stats.TotalBooks > 0 {
-		stats.AveragePerMonth = float64(stats.TotalBooks) / 12.0
+		stats.AveragePerMonth = float64(stats.TotalBooks) / float64(stats.MonthsElapsed)
 	}
 	
+	stats.TopAuthors = topAuthors(books, 5)
+	stats.Decades = publicationDecades(books)
+	
 	return stats
 }
 
+// topAuthors ranks the primary authors of books by books read, then pages
+func topAuthors(books []Book, limit int) []AuthorCount {
+	byKey := make(map[string]*AuthorCount)
+	var keys []string
+	authorKeys := bookAuthorKeys(books)
+	for _, book := range books {
+		key := authorKeys[book.Author]
+		if key == "" {
+			continue
+		}
+		a, ok := byKey[key]
+		if !ok {
+			a = &AuthorCount{Author: book.Author}
+			byKey[key] = a
+			keys = append(keys, key)
+		}
+		a.Books++
+		if pages := book.GetPages(); pages > 0 {
+			a.Pages += pages
+		}
+	}
+	
+	result := make([]AuthorCount, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *byKey[key])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Books != result[j].Books {
+			return result[i].Books > result[j].Books
+		}
+		return result[i].Pages > result[j].Pages
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// publicationDecades counts books by the decade they were first
+// published, falling back to the edition's year. Undated books are skipped.
+func publicationDecades(books []Book) []DecadeCount {
+	counts := make(map[int]int)
+	for _, book := range books {
+		year := book.GetPublicationYear()
+		if year <= 0 {
+			continue
+		}
+		counts[year/10*10]++
+	}
+	
+	result := make([]DecadeCount, 0, len(counts))
+	for decade, count := range counts {
+		result = append(result, DecadeCount{Decade: decade, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Decade < result[j].Decade })
+	return result
+}
+
+// CalculateAuthorNovelty counts the authors read in a year who were new to
+// us and those we had finished a book by in an earlier year
+func CalculateAuthorNovelty(books []Book, year int) (newAuthors, returning int) {
+	firstRead := make(map[string]int)
+	readInYear := make(map[string]bool)
+	authorKeys := bookAuthorKeys(books)
+	for _, book := range books {
+		key := authorKeys[book.Author]
+		if key == "" {
+			continue
+		}
+		for _, date := range book.ReadDates() {
+			d, err := ParseDate(date)
+			if err != nil {
+				continue
+			}
+			if first, ok := firstRead[key]; !ok || d.Year < first {
+				firstRead[key] = d.Year
+			}
+			if d.Year == year {
+				readInYear[key] = true
+			}
+		}
+	}
+	
+	for key := range readInYear {
+		if firstRead[key] < year {
+			returning++
+		} else {
+			newAuthors++
+		}
+	}
+	return newAuthors, returning
+}
+
 // CalculateMonthlyBreakdown calculates book count per month
 func CalculateMonthlyBreakdown(books []Book) []MonthlyCount {
 	monthNames := []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
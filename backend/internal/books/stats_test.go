@@ -2,6 +2,7 @@ package books
 
 import (
 	"testing"
+	"time"
 )
 
 // T015: TestCalculateStatistics verifies basic statistics calculation
@@ -168,3 +169,128 @@ func TestMonthNamesCorrect(t *testing.T) {
 		}
 	}
 }
+
+// TestCalculateStatisticsCurrentYear verifies the monthly average uses
+// elapsed months mid-year
+func TestCalculateStatisticsCurrentYear(t *testing.T) {
+	// Given three books read by the end of March
+	books := []Book{
+		{Title: "Book 1", Pages: float64(300), DateRead: "2026/01/15"},
+		{Title: "Book 2", Pages: float64(250), DateRead: "2026/02/20"},
+		{Title: "Book 3", Pages: float64(400), DateRead: "2026/03/10"},
+	}
+	now := time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC)
+
+	// When calculating statistics for the current year
+	stats := CalculateStatisticsAt(books, 2026, now)
+
+	// Then the average should be over three months
+	if stats.MonthsElapsed != 3 {
+		t.Errorf("MonthsElapsed: got %d, want 3", stats.MonthsElapsed)
+	}
+	if stats.AveragePerMonth != 1 {
+		t.Errorf("AveragePerMonth: got %.2f, want 1", stats.AveragePerMonth)
+	}
+
+	// And a past year should still use twelve months
+	past := CalculateStatisticsAt(books, 2025, now)
+	if past.MonthsElapsed != 12 {
+		t.Errorf("Past MonthsElapsed: got %d, want 12", past.MonthsElapsed)
+	}
+}
+
+// TestCalculateStatisticsLengths verifies median, extremes and histogram
+func TestCalculateStatisticsLengths(t *testing.T) {
+	// Given books of varied length, one without a page count
+	books := []Book{
+		{Title: "Novella", Pages: float64(120)},
+		{Title: "Novel", Pages: float64(320)},
+		{Title: "Doorstop", Pages: float64(880)},
+		{Title: "Standard", Pages: float64(350)},
+		{Title: "Unknown"},
+	}
+
+	// When calculating statistics
+	stats := CalculateStatistics(books, 2025)
+
+	// Then the median is over books with pages
+	if stats.MedianPages != 335 {
+		t.Errorf("MedianPages: got %.1f, want 335", stats.MedianPages)
+	}
+	if stats.Longest == nil || stats.Longest.GetTitle() != "Doorstop" {
+		t.Errorf("Longest: got %v, want Doorstop", stats.Longest)
+	}
+	if stats.Shortest == nil || stats.Shortest.GetTitle() != "Novella" {
+		t.Errorf("Shortest: got %v, want Novella", stats.Shortest)
+	}
+
+	// And each book lands in one bucket
+	want := []int{1, 0, 2, 0, 1}
+	if len(stats.PageHistogram) != len(want) {
+		t.Fatalf("PageHistogram: got %d buckets, want %d", len(stats.PageHistogram), len(want))
+	}
+	for i, count := range want {
+		if stats.PageHistogram[i].Count != count {
+			t.Errorf("Bucket %s: got %d, want %d", stats.PageHistogram[i].Label, stats.PageHistogram[i].Count, count)
+		}
+	}
+}
+
+// TestCalculateStatisticsAuthorsAndDecades verifies top authors and
+// publication decades
+func TestCalculateStatisticsAuthorsAndDecades(t *testing.T) {
+	// Given books by repeated authors with original and edition years
+	books := []Book{
+		{Title: "A", Author: "Ursula K. Le Guin", Pages: float64(200), OriginalPublicationYear: float64(1969), YearPublished: float64(2000)},
+		{Title: "B", Author: "Ursula K. Le Guin", Pages: float64(300), OriginalPublicationYear: float64(1974)},
+		{Title: "C", Author: "Octavia E. Butler", Pages: float64(600), YearPublished: float64(1993)},
+		{Title: "D", Author: "Octavia E. Butler", Pages: float64(300), OriginalPublicationYear: float64(1998)},
+		{Title: "E", Author: "N. K. Jemisin", Pages: float64(400)},
+	}
+
+	// When calculating statistics
+	stats := CalculateStatistics(books, 2025)
+
+	// Then authors rank by books, then pages
+	if len(stats.TopAuthors) != 3 {
+		t.Fatalf("TopAuthors: got %d, want 3", len(stats.TopAuthors))
+	}
+	if stats.TopAuthors[0].Author != "Octavia E. Butler" || stats.TopAuthors[0].Books != 2 || stats.TopAuthors[0].Pages != 900 {
+		t.Errorf("TopAuthors[0]: got %+v", stats.TopAuthors[0])
+	}
+	if stats.TopAuthors[1].Author != "Ursula K. Le Guin" {
+		t.Errorf("TopAuthors[1]: got %+v", stats.TopAuthors[1])
+	}
+
+	// And decades prefer the original publication year
+	want := []DecadeCount{{Decade: 1960, Count: 1}, {Decade: 1970, Count: 1}, {Decade: 1990, Count: 2}}
+	if len(stats.Decades) != len(want) {
+		t.Fatalf("Decades: got %+v, want %+v", stats.Decades, want)
+	}
+	for i := range want {
+		if stats.Decades[i] != want[i] {
+			t.Errorf("Decades[%d]: got %+v, want %+v", i, stats.Decades[i], want[i])
+		}
+	}
+}
+
+// TestCalculateAuthorNovelty verifies new and returning authors
+func TestCalculateAuthorNovelty(t *testing.T) {
+	// Given authors first read before and during 2025
+	books := []Book{
+		{Title: "Old", Author: "Returning Author", Shelf: "read", DateRead: "2023/05/01"},
+		{Title: "Again", Author: "Returning Author", Shelf: "read", DateRead: "2025/02/01"},
+		{Title: "First", Author: "New Author", Shelf: "read", DateRead: "2025/03/01"},
+		{Title: "Second", Author: "New Author", Shelf: "read", DateRead: "2025/06/01"},
+		{Title: "Later", Author: "Future Author", Shelf: "read", DateRead: "2026/01/01"},
+		{Title: "Someday", Author: "Unread Author", Shelf: "to-read"},
+	}
+
+	// When counting authors for 2025
+	newAuthors, returning := CalculateAuthorNovelty(books, 2025)
+
+	// Then each author counts once
+	if newAuthors != 1 || returning != 1 {
+		t.Errorf("Novelty: got %d new, %d returning, want 1 and 1", newAuthors, returning)
+	}
+}
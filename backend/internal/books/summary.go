@@ -0,0 +1,176 @@
+package books
+
+import "time"
+
+// Goal outcomes for a year
+const (
+	GoalMet        = "met"
+	GoalMissed     = "missed"
+	GoalInProgress = "in progress"
+)
+
+// YearSummary is one year's reading compared with the year before
+type YearSummary struct {
+	Year       int
+	Books      int
+	Pages      int
+	AvgPages   float64 // over books with a page count
+	GoalTarget int     // 0 when no goal was set
+	GoalStatus string  // met, missed or in progress; "" without a goal
+	BooksDelta int     // change from the previous year
+	PagesDelta int
+}
+
+// MonthTotal is the reading finished in one calendar month
+type MonthTotal struct {
+	Year  int
+	Month int
+	Books int
+	Pages int
+}
+
+// ReadingSummary compares every year of reading and totals them
+type ReadingSummary struct {
+	Years         []YearSummary // oldest first, including years with no reads
+	TotalBooks    int
+	TotalPages    int
+	AvgPages      float64
+	AvgBooksYear  float64
+	BestMonth     *MonthTotal // most books, then most pages
+	WorstMonth    *MonthTotal // fewest books among months with any reading
+	BestYear      int
+	LongestStreak int // consecutive years meeting the goal
+}
+
+// CalculateSummary totals every finished read (see FinishedReads) by year
+// and month in a single pass. goals maps years to book targets; a goal for
+// the year containing now that isn't yet met is in progress.
+func CalculateSummary(books []Book, goals map[int]int, now time.Time) ReadingSummary {
+	type totals struct{ books, pages, paged int }
+	years := make(map[int]*totals)
+	months := make(map[[2]int]*MonthTotal)
+	summary := ReadingSummary{}
+	pagedBooks := 0
+
+	for _, book := range books {
+		pages := book.GetPages()
+		for _, date := range book.ReadDates() {
+			d, err := ParseDate(date)
+			if err != nil {
+				continue
+			}
+			y, ok := years[d.Year]
+			if !ok {
+				y = &totals{}
+				years[d.Year] = y
+			}
+			y.books++
+			summary.TotalBooks++
+			if pages > 0 {
+				y.pages += pages
+				y.paged++
+				summary.TotalPages += pages
+				pagedBooks++
+			}
+
+			if d.Month == 0 {
+				continue
+			}
+			key := [2]int{d.Year, d.Month}
+			m, ok := months[key]
+			if !ok {
+				m = &MonthTotal{Year: d.Year, Month: d.Month}
+				months[key] = m
+			}
+			m.Books++
+			if pages > 0 {
+				m.Pages += pages
+			}
+		}
+	}
+
+	if len(years) == 0 {
+		return summary
+	}
+
+	first, last := 0, 0
+	for year := range years {
+		if first == 0 || year < first {
+			first = year
+		}
+		if year > last {
+			last = year
+		}
+	}
+
+	var prev YearSummary
+	streak := 0
+	bestBooks := -1
+	for year := first; year <= last; year++ {
+		ys := YearSummary{Year: year, GoalTarget: goals[year]}
+		if t, ok := years[year]; ok {
+			ys.Books = t.books
+			ys.Pages = t.pages
+			if t.paged > 0 {
+				ys.AvgPages = roundCents(float64(t.pages) / float64(t.paged))
+			}
+		}
+		if year > first {
+			ys.BooksDelta = ys.Books - prev.Books
+			ys.PagesDelta = ys.Pages - prev.Pages
+		}
+		if ys.GoalTarget > 0 {
+			switch {
+			case ys.Books >= ys.GoalTarget:
+				ys.GoalStatus = GoalMet
+			case year >= now.Year():
+				ys.GoalStatus = GoalInProgress
+			default:
+				ys.GoalStatus = GoalMissed
+			}
+		}
+
+		if ys.GoalStatus == GoalMet {
+			streak++
+			if streak > summary.LongestStreak {
+				summary.LongestStreak = streak
+			}
+		} else if ys.GoalStatus != GoalInProgress {
+			streak = 0
+		}
+		if ys.Books > bestBooks {
+			bestBooks = ys.Books
+			summary.BestYear = year
+		}
+
+		summary.Years = append(summary.Years, ys)
+		prev = ys
+	}
+
+	if pagedBooks > 0 {
+		summary.AvgPages = roundCents(float64(summary.TotalPages) / float64(pagedBooks))
+	}
+	summary.AvgBooksYear = roundCents(float64(summary.TotalBooks) / float64(len(summary.Years)))
+
+	for _, m := range months {
+		if summary.BestMonth == nil || monthRanksBelow(*summary.BestMonth, *m) {
+			summary.BestMonth = m
+		}
+		if summary.WorstMonth == nil || monthRanksBelow(*m, *summary.WorstMonth) {
+			summary.WorstMonth = m
+		}
+	}
+	return summary
+}
+
+// monthRanksBelow orders months by books then pages, breaking ties so the
+// earlier month ranks higher
+func monthRanksBelow(a, b MonthTotal) bool {
+	if a.Books != b.Books {
+		return a.Books < b.Books
+	}
+	if a.Pages != b.Pages {
+		return a.Pages < b.Pages
+	}
+	return a.Year*12+a.Month > b.Year*12+b.Month
+}
@@ -0,0 +1,63 @@
+package books
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCalculateSummary verifies per-year totals, deltas, goals and best months
+func TestCalculateSummary(t *testing.T) {
+	// Given
+	books := []Book{
+		{Title: "A", Shelf: "read", DateRead: "2022/01/10", Pages: 300},
+		{Title: "B", Shelf: "read", DateRead: "2022/01/20", Pages: 100},
+		{Title: "C", Shelf: "read", DateRead: "2024/05/01", Pages: 500},
+		{Title: "D", Shelf: "read", DateRead: "2025/02/01"},
+		{Title: "E", Shelf: "read", Reads: []Read{{DateFinished: "2022/06/01"}, {DateFinished: "2025/03/01"}}, Pages: 200},
+		{Title: "F", Shelf: "to-read", Pages: 900},
+	}
+	goals := map[int]int{2022: 3, 2024: 2, 2025: 5}
+	now := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	// When
+	summary := CalculateSummary(books, goals, now)
+
+	// Then
+	if len(summary.Years) != 4 {
+		t.Fatalf("Expected 2022-2025, got %d years", len(summary.Years))
+	}
+	y2022, y2023, y2024, y2025 := summary.Years[0], summary.Years[1], summary.Years[2], summary.Years[3]
+	if y2022.Books != 3 || y2022.Pages != 600 || y2022.AvgPages != 200 || y2022.GoalStatus != GoalMet {
+		t.Errorf("Unexpected 2022: %+v", y2022)
+	}
+	if y2023.Books != 0 || y2023.BooksDelta != -3 || y2023.GoalStatus != "" {
+		t.Errorf("Unexpected 2023: %+v", y2023)
+	}
+	if y2024.BooksDelta != 1 || y2024.PagesDelta != 500 || y2024.GoalStatus != GoalMissed {
+		t.Errorf("Unexpected 2024: %+v", y2024)
+	}
+	if y2025.Books != 2 || y2025.GoalStatus != GoalInProgress {
+		t.Errorf("Unexpected 2025: %+v", y2025)
+	}
+
+	if summary.TotalBooks != 6 || summary.TotalPages != 1300 {
+		t.Errorf("Lifetime: got %d books and %d pages, want 6 and 1300", summary.TotalBooks, summary.TotalPages)
+	}
+	if summary.BestMonth == nil || summary.BestMonth.Year != 2022 || summary.BestMonth.Month != 1 {
+		t.Errorf("Expected best month 2022-01, got %+v", summary.BestMonth)
+	}
+	if summary.WorstMonth == nil || summary.WorstMonth.Books != 1 || summary.WorstMonth.Pages != 0 {
+		t.Errorf("Expected worst month to be the page-less single book, got %+v", summary.WorstMonth)
+	}
+	if summary.BestYear != 2022 || summary.LongestStreak != 1 {
+		t.Errorf("Expected best year 2022 and streak 1, got %d and %d", summary.BestYear, summary.LongestStreak)
+	}
+}
+
+// TestCalculateSummaryEmpty verifies no reads gives an empty summary
+func TestCalculateSummaryEmpty(t *testing.T) {
+	summary := CalculateSummary(nil, nil, time.Now())
+	if len(summary.Years) != 0 || summary.BestMonth != nil {
+		t.Errorf("Expected empty summary, got %+v", summary)
+	}
+}
@@ -0,0 +1,57 @@
+package books
+
+import "strings"
+
+// exclusiveShelves are the Goodreads shelves a book sits on exactly one of.
+// Any other shelf in Bookshelves is a tag.
+var exclusiveShelves = map[string]bool{
+	"read":              true,
+	"to-read":           true,
+	"currently-reading": true,
+	ShelfDNF:            true,
+}
+
+// ParseTags splits a comma-separated tag list, normalizing each tag like a
+// shelf name and dropping blanks and repeats
+func ParseTags(s string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, t := range strings.Split(s, ",") {
+		t = NormalizeShelf(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		tags = append(tags, t)
+	}
+	return tags
+}
+
+// GetTags returns the book's tags: its Goodreads bookshelves other than the
+// exclusive read, to-read, currently-reading and dnf shelves
+func (b *Book) GetTags() []string {
+	var tags []string
+	for _, t := range ParseTags(b.Bookshelves) {
+		if !exclusiveShelves[t] {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// HasTag reports whether the book carries a tag
+func (b *Book) HasTag(tag string) bool {
+	tag = NormalizeShelf(tag)
+	for _, t := range b.GetTags() {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// JoinShelves builds a Goodreads-style Bookshelves value from the exclusive
+// shelf and comma-separated tags
+func JoinShelves(shelf, tags string) string {
+	return strings.Join(append(ParseTags(shelf), ParseTags(tags)...), ", ")
+}
@@ -0,0 +1,28 @@
+package books
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestGetTags verifies exclusive shelves are not treated as tags
+func TestGetTags(t *testing.T) {
+	book := Book{Bookshelves: "to-read, Favorites, sci-fi, favorites, did-not-finish", Shelf: "to-read"}
+	want := []string{"favorites", "sci-fi"}
+	if got := book.GetTags(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTags: got %v, want %v", got, want)
+	}
+	if !book.HasTag("Sci-Fi") {
+		t.Errorf("Expected HasTag to ignore case")
+	}
+}
+
+// TestJoinShelves verifies the Goodreads Bookshelves value is rebuilt
+func TestJoinShelves(t *testing.T) {
+	if got := JoinShelves("read", "favorites, sci-fi"); got != "read, favorites, sci-fi" {
+		t.Errorf("JoinShelves: got %q", got)
+	}
+	if got := JoinShelves("read", ""); got != "read" {
+		t.Errorf("JoinShelves without tags: got %q", got)
+	}
+}
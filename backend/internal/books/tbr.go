@@ -0,0 +1,182 @@
+package books
+
+import (
+	"sort"
+	"time"
+)
+
+// TBRMonth is the to-read backlog over one calendar month
+type TBRMonth struct {
+	Month     string // YYYY-MM
+	Added     int    // books shelved to read
+	Completed int    // books that left the backlog by being read or abandoned
+	Size      int    // backlog at the end of the month
+}
+
+// TBRAgeBucket counts to-read books by how long they have waited
+type TBRAgeBucket struct {
+	Label string
+	Count int
+}
+
+// TBREntry is a book on the to-read shelf and how long it has waited
+type TBREntry struct {
+	Book      Book
+	DateAdded string
+	AgeDays   int
+}
+
+// TBRReport describes the health of the to-read backlog
+type TBRReport struct {
+	Size          int // books on the to-read shelf now
+	History       []TBRMonth
+	AgeBuckets    []TBRAgeBucket
+	Oldest        []TBREntry // up to ten, oldest first
+	MedianAgeDays float64
+	// CompletionsPerYear is the pace over the last twelve months
+	CompletionsPerYear int
+	AdditionsPerYear   int
+	// YearsToClear is Size at the net pace of completions minus additions
+	// over the last twelve months; 0 when the backlog isn't shrinking
+	YearsToClear float64
+}
+
+// tbrAgeBuckets are the upper bounds, in days, of each age bucket
+var tbrAgeBuckets = []struct {
+	label   string
+	maxDays int
+}{
+	{"under 6 months", 182},
+	{"6-12 months", 365},
+	{"1-2 years", 2 * 365},
+	{"2-5 years", 5 * 365},
+	{"5+ years", -1},
+}
+
+// tbrExit returns when a book left the to-read backlog: when it was
+// started, finished or abandoned, whichever came first after it was added
+func tbrExit(book Book, added time.Time) (time.Time, bool) {
+	var exit time.Time
+	consider := func(date string) {
+		if t, ok := parseLooseDate(date); ok && !t.Before(added) && (exit.IsZero() || t.Before(exit)) {
+			exit = t
+		}
+	}
+	for _, r := range book.AllReads() {
+		consider(r.DateStarted)
+		consider(r.DateFinished)
+	}
+	consider(book.DNFDate)
+	return exit, !exit.IsZero()
+}
+
+// CalculateTBRReport reconstructs the to-read backlog month by month from
+// DateAdded and the dates books were started, read or abandoned. A book
+// that was finished before it was added never sat on the backlog. The
+// report runs to the month containing now.
+func CalculateTBRReport(books []Book, now time.Time) TBRReport {
+	report := TBRReport{}
+	added := make(map[string]int)
+	completed := make(map[string]int)
+	var first time.Time
+	yearAgo := now.AddDate(-1, 0, 0)
+
+	for _, book := range books {
+		shelf := NormalizeShelf(book.Shelf)
+		addedAt, hasAdded := parseLooseDate(book.GetDateAdded())
+
+		if shelf == ShelfToRead {
+			report.Size++
+			if hasAdded {
+				report.Oldest = append(report.Oldest, TBREntry{
+					Book:      book,
+					DateAdded: book.GetDateAdded(),
+					AgeDays:   int(now.Sub(addedAt).Hours() / 24),
+				})
+			}
+		}
+		if !hasAdded || addedAt.After(now) {
+			continue
+		}
+
+		exit, left := tbrExit(book, addedAt)
+		if shelf != ShelfToRead && !left {
+			// Read or in progress with no usable dates after shelving:
+			// it was never waiting on the backlog
+			continue
+		}
+		if left && exit.Equal(addedAt) {
+			continue
+		}
+
+		added[addedAt.Format("2006-01")]++
+		if first.IsZero() || addedAt.Before(first) {
+			first = addedAt
+		}
+		if !addedAt.Before(yearAgo) {
+			report.AdditionsPerYear++
+		}
+		if left && !exit.After(now) {
+			completed[exit.Format("2006-01")]++
+			if !exit.Before(yearAgo) {
+				report.CompletionsPerYear++
+			}
+		}
+	}
+
+	// Month-by-month history
+	if !first.IsZero() {
+		size := 0
+		for m := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC); !m.After(now); m = m.AddDate(0, 1, 0) {
+			key := m.Format("2006-01")
+			size += added[key] - completed[key]
+			report.History = append(report.History, TBRMonth{
+				Month:     key,
+				Added:     added[key],
+				Completed: completed[key],
+				Size:      size,
+			})
+		}
+	}
+
+	// Age distribution of the current backlog
+	counts := make([]int, len(tbrAgeBuckets))
+	ages := make([]int, 0, len(report.Oldest))
+	for _, e := range report.Oldest {
+		ages = append(ages, e.AgeDays)
+		for i, b := range tbrAgeBuckets {
+			if b.maxDays < 0 || e.AgeDays < b.maxDays {
+				counts[i]++
+				break
+			}
+		}
+	}
+	for i, b := range tbrAgeBuckets {
+		report.AgeBuckets = append(report.AgeBuckets, TBRAgeBucket{Label: b.label, Count: counts[i]})
+	}
+	if unknown := report.Size - len(report.Oldest); unknown > 0 {
+		report.AgeBuckets = append(report.AgeBuckets, TBRAgeBucket{Label: "unknown", Count: unknown})
+	}
+
+	if len(ages) > 0 {
+		sort.Ints(ages)
+		mid := len(ages) / 2
+		if len(ages)%2 == 0 {
+			report.MedianAgeDays = float64(ages[mid-1]+ages[mid]) / 2
+		} else {
+			report.MedianAgeDays = float64(ages[mid])
+		}
+	}
+
+	sort.SliceStable(report.Oldest, func(i, j int) bool {
+		return report.Oldest[i].AgeDays > report.Oldest[j].AgeDays
+	})
+	if len(report.Oldest) > 10 {
+		report.Oldest = report.Oldest[:10]
+	}
+
+	if net := report.CompletionsPerYear - report.AdditionsPerYear; net > 0 {
+		report.YearsToClear = roundCents(float64(report.Size) / float64(net))
+	}
+	return report
+}
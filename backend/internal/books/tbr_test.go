@@ -0,0 +1,100 @@
+package books
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCalculateTBRReport verifies the backlog is reconstructed month by month
+func TestCalculateTBRReport(t *testing.T) {
+	// Given
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	books := []Book{
+		// Waiting since 2018
+		{Title: "Old", Author: "A", Shelf: "to-read", DateAdded: "2018/01/10"},
+		// Added in January, read in February
+		{Title: "Cleared", Author: "B", Shelf: "read", DateAdded: "2025/01/05", DateRead: "2025/02/20"},
+		// Added in February, still waiting
+		{Title: "New", Author: "C", Shelf: "to-read", DateAdded: "2025/02/01"},
+		// Shelved straight to read: never on the backlog
+		{Title: "Straight", Author: "D", Shelf: "read", DateAdded: "2025/03/01", DateRead: "2025/03/01"},
+		// Read years before it was added
+		{Title: "Backfilled", Author: "E", Shelf: "read", DateAdded: "2025/01/01", DateRead: "2010/01/01"},
+		// No date added
+		{Title: "Undated", Author: "F", Shelf: "to-read"},
+	}
+
+	// When
+	report := CalculateTBRReport(books, now)
+
+	// Then
+	if report.Size != 3 {
+		t.Errorf("Size: got %d, want 3", report.Size)
+	}
+	last := report.History[len(report.History)-1]
+	if last.Month != "2025-03" || last.Size != 2 {
+		t.Errorf("Expected 2 dated books waiting in 2025-03, got %+v", last)
+	}
+	feb := report.History[len(report.History)-2]
+	if feb.Month != "2025-02" || feb.Added != 1 || feb.Completed != 1 || feb.Size != 2 {
+		t.Errorf("Unexpected February: %+v", feb)
+	}
+	if report.History[0].Month != "2018-01" {
+		t.Errorf("Expected history to start 2018-01, got %s", report.History[0].Month)
+	}
+
+	if len(report.Oldest) != 2 || report.Oldest[0].Book.GetTitle() != "Old" {
+		t.Errorf("Expected Old first, got %+v", report.Oldest)
+	}
+	buckets := make(map[string]int)
+	for _, b := range report.AgeBuckets {
+		buckets[b.Label] = b.Count
+	}
+	if buckets["under 6 months"] != 1 || buckets["5+ years"] != 1 || buckets["unknown"] != 1 {
+		t.Errorf("Unexpected age buckets: %+v", report.AgeBuckets)
+	}
+
+	if report.CompletionsPerYear != 1 || report.AdditionsPerYear != 2 {
+		t.Errorf("Expected 1 completion and 2 additions this year, got %d and %d",
+			report.CompletionsPerYear, report.AdditionsPerYear)
+	}
+	// More additions than completions: the backlog never clears
+	if report.YearsToClear != 0 {
+		t.Errorf("YearsToClear: got %v, want 0", report.YearsToClear)
+	}
+}
+
+// TestCalculateTBRReportYearsToClear verifies the projection uses the net
+// pace of completions minus additions
+func TestCalculateTBRReportYearsToClear(t *testing.T) {
+	// Given
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	books := []Book{
+		{Title: "Waiting", Author: "A", Shelf: "to-read", DateAdded: "2020/01/01"},
+		{Title: "Also Waiting", Author: "B", Shelf: "to-read", DateAdded: "2020/01/01"},
+		{Title: "Added", Author: "C", Shelf: "to-read", DateAdded: "2024/12/01"},
+		{Title: "First", Author: "D", Shelf: "read", DateAdded: "2020/01/01", DateRead: "2024/06/01"},
+		{Title: "Second", Author: "E", Shelf: "read", DateAdded: "2020/01/01", DateRead: "2024/09/01"},
+		{Title: "Third", Author: "F", Shelf: "read", DateAdded: "2020/01/01", DateRead: "2025/01/01"},
+	}
+
+	// When
+	report := CalculateTBRReport(books, now)
+
+	// Then three completions against one addition clear three books in 1.5 years
+	if report.CompletionsPerYear != 3 || report.AdditionsPerYear != 1 {
+		t.Fatalf("Expected 3 completions and 1 addition, got %d and %d",
+			report.CompletionsPerYear, report.AdditionsPerYear)
+	}
+	if report.YearsToClear != 1.5 {
+		t.Errorf("YearsToClear: got %v, want 1.5", report.YearsToClear)
+	}
+}
+
+// TestCalculateTBRReportEmpty verifies an empty library reports nothing
+func TestCalculateTBRReportEmpty(t *testing.T) {
+	report := CalculateTBRReport(nil, time.Now())
+	if report.Size != 0 || len(report.History) != 0 || report.YearsToClear != 0 {
+		t.Errorf("Expected empty report, got %+v", report)
+	}
+}
@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/kristenwomack/reading-app/backend/internal/auth"
+	"github.com/kristenwomack/reading-app/backend/internal/books"
 	"github.com/kristenwomack/reading-app/backend/internal/store"
 )
 
@@ -82,20 +84,92 @@ func CheckAuth(w http.ResponseWriter, r *http.Request) {
 
 // BookRequest represents a book creation/update request
 type BookRequest struct {
-	Title                   string `json:"title"`
-	Author                  string `json:"author"`
-	AdditionalAuthors       string `json:"additionalAuthors"`
-	ISBN                    string `json:"isbn"`
-	ISBN13                  string `json:"isbn13"`
-	Publisher               string `json:"publisher"`
-	Pages                   int    `json:"pages"`
-	YearPublished           int    `json:"yearPublished"`
-	OriginalPublicationYear int    `json:"originalPublicationYear"`
-	DateRead                string `json:"dateRead"`
-	DateAdded               string `json:"dateAdded"`
-	Shelf                   string `json:"shelf"`
-	Review                  string `json:"review"`
-	CoverURL                string `json:"coverUrl"`
+	Title                   string  `json:"title"`
+	Author                  string  `json:"author"`
+	AdditionalAuthors       string  `json:"additionalAuthors"`
+	ISBN                    string  `json:"isbn"`
+	ISBN13                  string  `json:"isbn13"`
+	Publisher               string  `json:"publisher"`
+	Pages                   int     `json:"pages"`
+	YearPublished           int     `json:"yearPublished"`
+	OriginalPublicationYear int     `json:"originalPublicationYear"`
+	DateStarted             string  `json:"dateStarted"`
+	DateRead                string  `json:"dateRead"`
+	DateAdded               string  `json:"dateAdded"`
+	Shelf                   string  `json:"shelf"`
+	Review                  string  `json:"review"`
+	CoverURL                string  `json:"coverUrl"`
+	Series                  string  `json:"series"`
+	SeriesPosition          float64 `json:"seriesPosition"`
+	Format                  string  `json:"format"`
+	DurationMinutes         int     `json:"durationMinutes"`
+	DNFDate                 string  `json:"dnfDate"`
+	DNFPage                 int     `json:"dnfPage"`
+	DNFPercent              float64 `json:"dnfPercent"`
+	DNFReason               string  `json:"dnfReason"`
+	Ownership               string  `json:"ownership"`
+	OwnedFormat             string  `json:"ownedFormat"`
+	PurchaseDate            string  `json:"purchaseDate"`
+	PurchasePrice           float64 `json:"purchasePrice"`
+	ShelfLocation           string  `json:"shelfLocation"`
+	Tags                    string  `json:"tags"`
+	Rating                  float64 `json:"rating"`
+}
+
+// normalizeDate checks an optional YYYY/MM/DD date, possibly without a day
+// or month, and zero-pads it so dates compare correctly as strings
+func normalizeDate(date string) (string, error) {
+	if date == "" {
+		return "", nil
+	}
+	parsed, err := books.ParseDate(date)
+	if err != nil {
+		return "", err
+	}
+	return parsed.String(), nil
+}
+
+// validate checks optional fields shared by create and update, and maps
+// did-not-finish shelf spellings onto the dnf shelf
+func (req *BookRequest) validate() error {
+	if req.Format != "" && books.NormalizeFormat(req.Format) == "" {
+		return fmt.Errorf("format must be print, ebook or audiobook")
+	}
+	if req.DurationMinutes < 0 {
+		return fmt.Errorf("duration must not be negative")
+	}
+	if req.DNFPage < 0 || req.DNFPercent < 0 || req.DNFPercent > 100 {
+		return fmt.Errorf("dnf page must not be negative and dnf percent must be 0-100")
+	}
+	var err error
+	if req.DateStarted, err = normalizeDate(req.DateStarted); err != nil {
+		return fmt.Errorf("invalid start date")
+	}
+	if req.DateRead, err = normalizeDate(req.DateRead); err != nil {
+		return fmt.Errorf("invalid date read")
+	}
+	if req.DNFDate, err = normalizeDate(req.DNFDate); err != nil {
+		return fmt.Errorf("invalid dnf date")
+	}
+	if req.Ownership != "" && books.NormalizeOwnership(req.Ownership) == "" {
+		return fmt.Errorf("ownership must be owned, borrowed, library or wishlist")
+	}
+	if req.OwnedFormat != "" && books.NormalizeFormat(req.OwnedFormat) == "" {
+		return fmt.Errorf("owned format must be print, ebook or audiobook")
+	}
+	if req.PurchasePrice < 0 {
+		return fmt.Errorf("purchase price must not be negative")
+	}
+	if req.Rating < 0 || req.Rating > 5 || books.QuarterStars(req.Rating) != req.Rating {
+		return fmt.Errorf("rating must be 0-5 in quarter stars")
+	}
+	if req.PurchaseDate, err = normalizeDate(req.PurchaseDate); err != nil {
+		return fmt.Errorf("invalid purchase date")
+	}
+	if books.NormalizeShelf(req.Shelf) == books.ShelfDNF {
+		req.Shelf = books.ShelfDNF
+	}
+	return nil
 }
 
 // CreateBook handles POST /api/books
@@ -116,6 +190,11 @@ func CreateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	if req.Shelf == "" {
 		req.Shelf = "read"
 	}
@@ -130,11 +209,27 @@ func CreateBook(w http.ResponseWriter, r *http.Request) {
 		Pages:                   req.Pages,
 		YearPublished:           req.YearPublished,
 		OriginalPublicationYear: req.OriginalPublicationYear,
+		DateStarted:             req.DateStarted,
 		DateRead:                req.DateRead,
 		DateAdded:               req.DateAdded,
 		Shelf:                   req.Shelf,
 		Review:                  req.Review,
 		CoverURL:                req.CoverURL,
+		Series:                  req.Series,
+		SeriesPosition:          req.SeriesPosition,
+		Format:                  books.NormalizeFormat(req.Format),
+		DurationMinutes:         req.DurationMinutes,
+		DNFDate:                 req.DNFDate,
+		DNFPage:                 req.DNFPage,
+		DNFPercent:              req.DNFPercent,
+		DNFReason:               req.DNFReason,
+		Ownership:               books.NormalizeOwnership(req.Ownership),
+		OwnedFormat:             books.NormalizeFormat(req.OwnedFormat),
+		PurchaseDate:            req.PurchaseDate,
+		PurchasePrice:           req.PurchasePrice,
+		ShelfLocation:           req.ShelfLocation,
+		Tags:                    strings.Join(books.ParseTags(req.Tags), ", "),
+		Rating:                  req.Rating,
 	}
 
 	id, err := dataStore.CreateBook(book)
@@ -169,6 +264,11 @@ func UpdateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	book := &store.Book{
 		ID:                      id,
 		Title:                   req.Title,
@@ -180,11 +280,27 @@ func UpdateBook(w http.ResponseWriter, r *http.Request) {
 		Pages:                   req.Pages,
 		YearPublished:           req.YearPublished,
 		OriginalPublicationYear: req.OriginalPublicationYear,
+		DateStarted:             req.DateStarted,
 		DateRead:                req.DateRead,
 		DateAdded:               req.DateAdded,
 		Shelf:                   req.Shelf,
 		Review:                  req.Review,
 		CoverURL:                req.CoverURL,
+		Series:                  req.Series,
+		SeriesPosition:          req.SeriesPosition,
+		Format:                  books.NormalizeFormat(req.Format),
+		DurationMinutes:         req.DurationMinutes,
+		DNFDate:                 req.DNFDate,
+		DNFPage:                 req.DNFPage,
+		DNFPercent:              req.DNFPercent,
+		DNFReason:               req.DNFReason,
+		Ownership:               books.NormalizeOwnership(req.Ownership),
+		OwnedFormat:             books.NormalizeFormat(req.OwnedFormat),
+		PurchaseDate:            req.PurchaseDate,
+		PurchasePrice:           req.PurchasePrice,
+		ShelfLocation:           req.ShelfLocation,
+		Tags:                    strings.Join(books.ParseTags(req.Tags), ", "),
+		Rating:                  req.Rating,
 	}
 
 	if err := dataStore.UpdateBook(book); err != nil {
@@ -220,64 +336,6 @@ func DeleteBook(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]bool{"success": true})
 }
 
-// exportBook matches the books.json Goodreads-style format for round-trip compatibility
-type exportBook struct {
-	Title                   string `json:"Title"`
-	Author                  string `json:"Author"`
-	AdditionalAuthors       string `json:"Additional Authors"`
-	ISBN                    string `json:"ISBN"`
-	ISBN13                  string `json:"ISBN13"`
-	Publisher               string `json:"Publisher"`
-	Pages                   int    `json:"Number of Pages"`
-	YearPublished           int    `json:"Year Published"`
-	OriginalPublicationYear int    `json:"Original Publication Year"`
-	DateRead                string `json:"Date Read"`
-	DateAdded               string `json:"Date Added"`
-	Shelf                   string `json:"Shelf"`
-	MyReview                string `json:"My Review"`
-	CoverURL                string `json:"CoverURL,omitempty"`
-}
-
-// ExportBooks handles GET /api/export
-func ExportBooks(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	books, err := dataStore.GetAllBooks()
-	if err != nil {
-		http.Error(w, "Failed to get books", http.StatusInternalServerError)
-		return
-	}
-
-	exported := make([]exportBook, len(books))
-	for i, b := range books {
-		exported[i] = exportBook{
-			Title:                   b.Title,
-			Author:                  b.Author,
-			AdditionalAuthors:       b.AdditionalAuthors,
-			ISBN:                    b.ISBN,
-			ISBN13:                  b.ISBN13,
-			Publisher:               b.Publisher,
-			Pages:                   b.Pages,
-			YearPublished:           b.YearPublished,
-			OriginalPublicationYear: b.OriginalPublicationYear,
-			DateRead:                b.DateRead,
-			DateAdded:               b.DateAdded,
-			Shelf:                   b.Shelf,
-			MyReview:                b.Review,
-			CoverURL:                b.CoverURL,
-		}
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Content-Disposition", "attachment; filename=books.json")
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "  ")
-	enc.Encode(exported)
-}
-
 // GetGoal handles GET /api/goals/:year
 func GetGoal(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
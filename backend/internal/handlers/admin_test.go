@@ -1,15 +1,20 @@
 package handlers
 
 import (
+	"archive/zip"
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
 	"github.com/kristenwomack/reading-app/backend/internal/auth"
+	"github.com/kristenwomack/reading-app/backend/internal/books"
 	"github.com/kristenwomack/reading-app/backend/internal/store"
 )
 
@@ -327,6 +332,38 @@ func TestCreateBookMissingTitle(t *testing.T) {
 	}
 }
 
+// TestCreateBookRating verifies ratings are stored and out-of-range ones rejected
+func TestCreateBookRating(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	// Execute
+	body, _ := json.Marshal(map[string]interface{}{"title": "Rated", "author": "Author", "rating": 3.75})
+	w := httptest.NewRecorder()
+	CreateBook(w, httptest.NewRequest(http.MethodPost, "/api/books", bytes.NewBuffer(body)))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	var response map[string]int64
+	json.NewDecoder(w.Body).Decode(&response)
+	book, err := s.GetBook(response["id"])
+	if err != nil || book == nil || book.Rating != 3.75 {
+		t.Errorf("Expected rating 3.75, got %v (err %v)", book, err)
+	}
+
+	for _, rating := range []float64{6, 3.3} {
+		body, _ = json.Marshal(map[string]interface{}{"title": "Overrated", "author": "Author", "rating": rating})
+		w = httptest.NewRecorder()
+		CreateBook(w, httptest.NewRequest(http.MethodPost, "/api/books", bytes.NewBuffer(body)))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Rating %g: expected status %d, got %d", rating, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
 // TestCreateBookInvalidJSON verifies error with malformed JSON
 func TestCreateBookInvalidJSON(t *testing.T) {
 	// Setup test database
@@ -790,3 +827,1171 @@ func TestAuthMiddleware(t *testing.T) {
 		}
 	})
 }
+
+// TestGetAuthor verifies the author detail endpoint
+func TestGetAuthor(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	if _, err := s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", Pages: 264, DateRead: "2023/02/01", Shelf: "read"}); err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+	authors, err := s.GetAuthors()
+	if err != nil || len(authors) != 1 {
+		t.Fatalf("Expected 1 author, got %d (err %v)", len(authors), err)
+	}
+
+	idStr := fmt.Sprintf("%d", authors[0].ID)
+	req := httptest.NewRequest(http.MethodGet, "/api/authors/"+idStr, nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetAuthor(w, req)
+
+	// Verify response code
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response struct {
+		Author struct {
+			Name      string `json:"name"`
+			BooksRead int    `json:"booksRead"`
+		} `json:"author"`
+		Books []struct {
+			Title string `json:"title"`
+			Role  string `json:"role"`
+		} `json:"books"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Author.Name != "Octavia E. Butler" || response.Author.BooksRead != 1 {
+		t.Errorf("Unexpected author: %+v", response.Author)
+	}
+	if len(response.Books) != 1 || response.Books[0].Role != "author" {
+		t.Errorf("Unexpected books: %+v", response.Books)
+	}
+}
+
+// TestGetAuthorNotFound verifies 404 for unknown authors
+func TestGetAuthorNotFound(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/authors/999", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetAuthor(w, req)
+
+	// Verify response code
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// TestMergeAuthorsSameID verifies merging an author into itself is rejected
+func TestMergeAuthorsSameID(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	body := bytes.NewBufferString(`{"sourceId":1,"targetId":1}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/authors/merge", body)
+	w := httptest.NewRecorder()
+
+	// Execute
+	MergeAuthors(w, req)
+
+	// Verify response code
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// TestAddRead verifies recording a re-read through the API
+func TestAddRead(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	id, err := s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", DateRead: "2023/02/01", Shelf: "read"})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	path := fmt.Sprintf("/api/books/%d/reads", id)
+	body := bytes.NewBufferString(`{"dateFinished":"2025/03/09","format":"audiobook"}`)
+	req := httptest.NewRequest(http.MethodPost, path, body)
+	w := httptest.NewRecorder()
+
+	// Execute
+	AddRead(w, req)
+
+	// Verify response code
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	// Verify both reads are listed
+	req = httptest.NewRequest(http.MethodGet, path, nil)
+	w = httptest.NewRecorder()
+	GetReads(w, req)
+
+	var response struct {
+		Reads []struct {
+			DateFinished string `json:"dateFinished"`
+		} `json:"reads"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Reads) != 2 {
+		t.Errorf("Expected 2 reads, got %d", len(response.Reads))
+	}
+}
+
+// TestAddReadPadsDates verifies unpadded dates are stored zero-padded, so
+// the latest read is picked correctly
+func TestAddReadPadsDates(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	id, err := s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+	path := fmt.Sprintf("/api/books/%d/reads", id)
+
+	// Execute
+	for _, body := range []string{`{"dateFinished":"2025/10/1"}`, `{"dateStarted":"2025/8/20","dateFinished":"2025/9/1"}`} {
+		w := httptest.NewRecorder()
+		AddRead(w, httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body)))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+		}
+	}
+
+	book, _ := s.GetBook(id)
+	if book.DateRead != "2025/10/01" {
+		t.Errorf("Expected date read 2025/10/01, got %q", book.DateRead)
+	}
+	reads, _ := s.GetReads(id)
+	if len(reads) != 2 || reads[0].DateStarted != "2025/08/20" || reads[0].DateFinished != "2025/09/01" {
+		t.Errorf("Expected padded reads in order, got %+v", reads)
+	}
+}
+
+// TestCreateBookPadsDates verifies book dates are stored zero-padded
+func TestCreateBookPadsDates(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	body := bytes.NewBufferString(`{"title":"Kindred","author":"Octavia E. Butler","shelf":"read","dateStarted":"2025/9/1","dateRead":"2025/9/20"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/books", body)
+	w := httptest.NewRecorder()
+
+	// Execute
+	CreateBook(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	all, _ := s.GetAllBooks()
+	if len(all) != 1 || all[0].DateStarted != "2025/09/01" || all[0].DateRead != "2025/09/20" {
+		t.Errorf("Expected padded dates, got %+v", all)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/books", bytes.NewBufferString(`{"title":"Dawn","author":"Octavia E. Butler","dateRead":"last week"}`))
+	w = httptest.NewRecorder()
+	CreateBook(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid date, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// TestAddReadInvalidDate verifies malformed dates are rejected
+func TestAddReadFormat(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	id, err := s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+	path := fmt.Sprintf("/api/books/%d/reads", id)
+
+	// Execute with an unknown format
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(`{"format":"scroll"}`))
+	w := httptest.NewRecorder()
+	AddRead(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// Execute with a format spelling that needs normalising
+	req = httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(`{"dateFinished":"2025/03/09","format":"Kindle Edition"}`))
+	w = httptest.NewRecorder()
+	AddRead(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	reads, err := s.GetReads(id)
+	if err != nil {
+		t.Fatalf("Failed to get reads: %v", err)
+	}
+	if len(reads) != 1 || reads[0].Format != "ebook" {
+		t.Errorf("Expected one ebook read, got %+v", reads)
+	}
+}
+
+func TestAddReadInvalidDate(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	body := bytes.NewBufferString(`{"dateFinished":"yesterday"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/books/1/reads", body)
+	w := httptest.NewRecorder()
+
+	// Execute
+	AddRead(w, req)
+
+	// Verify response code
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// TestCreateBookInvalidFormat verifies unknown formats are rejected
+func TestCreateBookInvalidFormat(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	body := bytes.NewBufferString(`{"title":"Dune","author":"Frank Herbert","format":"scroll"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/books", body)
+	w := httptest.NewRecorder()
+
+	// Execute
+	CreateBook(w, req)
+
+	// Verify response code
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// TestCreateNote verifies adding a highlight through the API
+func TestCreateNote(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	id, err := s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	path := fmt.Sprintf("/api/books/%d/notes", id)
+	body := bytes.NewBufferString(`{"kind":"highlight","text":"The ache of the past","location":"120-122"}`)
+	req := httptest.NewRequest(http.MethodPost, path, body)
+	w := httptest.NewRecorder()
+
+	// Execute
+	CreateNote(w, req)
+
+	// Verify response code
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	// Verify the note is listed
+	req = httptest.NewRequest(http.MethodGet, path, nil)
+	w = httptest.NewRecorder()
+	GetNotes(w, req)
+
+	var response struct {
+		Notes []struct {
+			Kind     string `json:"kind"`
+			Location string `json:"location"`
+		} `json:"notes"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Notes) != 1 || response.Notes[0].Kind != "highlight" || response.Notes[0].Location != "120-122" {
+		t.Errorf("Expected one highlight at 120-122, got %+v", response.Notes)
+	}
+}
+
+// TestCreateNoteValidation verifies bad kinds, empty text and missing books are rejected
+func TestCreateNoteValidation(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	id, _ := s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want int
+	}{
+		{"bad kind", fmt.Sprintf("/api/books/%d/notes", id), `{"kind":"doodle","text":"x"}`, http.StatusBadRequest},
+		{"empty text", fmt.Sprintf("/api/books/%d/notes", id), `{"kind":"note","text":"  "}`, http.StatusBadRequest},
+		{"missing book", "/api/books/999/notes", `{"text":"x"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+		w := httptest.NewRecorder()
+		CreateNote(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.want, w.Code)
+		}
+	}
+}
+
+// TestSearchHandler verifies search results include notes with their book
+func TestSearchHandler(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	id, _ := s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+	s.CreateNote(&store.Note{BookID: id, Kind: store.NoteKindQuote, Text: "Rufus called again"})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q=rufus", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	Search(w, req)
+
+	// Verify response code
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response struct {
+		Results []struct {
+			Kind   string `json:"kind"`
+			BookID int64  `json:"bookId"`
+			Title  string `json:"title"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Results) != 1 || response.Results[0].Kind != "note" || response.Results[0].BookID != id {
+		t.Errorf("Expected one note on book %d, got %+v", id, response.Results)
+	}
+}
+
+// TestImportClippingsHandler verifies a raw clippings upload is imported
+func TestImportClippingsHandler(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+
+	body := bytes.NewBufferString("Kindred (Octavia E. Butler)\n" +
+		"- Your Highlight on Location 100-102 | Added on Tuesday, March 5, 2024 7:30:00 AM\n\n" +
+		"I lost an arm on my last trip home.\n==========\n" +
+		"Wild Seed (Octavia E. Butler)\n" +
+		"- Your Highlight on Location 5 | Added on Tuesday, March 5, 2024 7:31:00 AM\n\n" +
+		"Doro\n==========\n")
+	req := httptest.NewRequest(http.MethodPost, "/api/import/clippings", body)
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+
+	// Execute
+	ImportClippings(w, req)
+
+	// Verify response code
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response struct {
+		Imported  int      `json:"imported"`
+		Unmatched []string `json:"unmatched"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Imported != 1 {
+		t.Errorf("Expected 1 imported, got %d", response.Imported)
+	}
+	if len(response.Unmatched) != 1 || response.Unmatched[0] != "Wild Seed (Octavia E. Butler)" {
+		t.Errorf("Expected Wild Seed unmatched, got %v", response.Unmatched)
+	}
+}
+
+// TestImportClippingsFormEncoded verifies a raw body sent with curl's
+// default form content type is read as the file, not parsed as a form
+func TestImportClippingsFormEncoded(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+
+	body := bytes.NewBufferString("Kindred (Octavia E. Butler)\n" +
+		"- Your Highlight on Location 100-102 | Added on Tuesday, March 5, 2024 7:30:00 AM\n\n" +
+		"I lost an arm on my last trip home.\n==========\n")
+	req := httptest.NewRequest(http.MethodPost, "/api/import/clippings", body)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	// Execute
+	ImportClippings(w, req)
+
+	var response struct {
+		Imported int `json:"imported"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if w.Code != http.StatusOK || response.Imported != 1 {
+		t.Errorf("Expected 1 imported, got %d (status %d)", response.Imported, w.Code)
+	}
+}
+
+// TestLendAndReturnBook verifies the lending round trip and overdue flags
+func TestLendAndReturnBook(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	id, _ := s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+	path := fmt.Sprintf("/api/books/%d/lend", id)
+
+	// Lend with a due date long past
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(`{"borrower":"Sam","lentDate":"2020/1/5","dueDate":"2020/02/05"}`))
+	w := httptest.NewRecorder()
+	LendBook(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	var created map[string]int64
+	json.NewDecoder(w.Body).Decode(&created)
+
+	// A second loan of the same copy conflicts
+	req = httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(`{"borrower":"Alex"}`))
+	w = httptest.NewRecorder()
+	LendBook(w, req)
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+
+	// The loan is outstanding and overdue
+	req = httptest.NewRequest(http.MethodGet, "/api/loans?status=outstanding", nil)
+	w = httptest.NewRecorder()
+	GetLoans(w, req)
+	var response struct {
+		Loans []struct {
+			LentDate string `json:"lentDate"`
+			Overdue  bool   `json:"overdue"`
+		} `json:"loans"`
+		Overdue int `json:"overdue"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Loans) != 1 || !response.Loans[0].Overdue || response.Overdue != 1 {
+		t.Fatalf("Expected one overdue loan, got %+v", response)
+	}
+	if response.Loans[0].LentDate != "2020/01/05" {
+		t.Errorf("Expected lent date padded to 2020/01/05, got %q", response.Loans[0].LentDate)
+	}
+
+	// Return it today
+	req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/loans/%d/return", created["id"]), nil)
+	w = httptest.NewRecorder()
+	ReturnLoan(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/loans?status=outstanding", nil)
+	w = httptest.NewRecorder()
+	GetLoans(w, req)
+	response.Loans = nil
+	json.NewDecoder(w.Body).Decode(&response)
+	if len(response.Loans) != 0 {
+		t.Errorf("Expected no outstanding loans, got %d", len(response.Loans))
+	}
+}
+
+// TestGetStatsSummary verifies goals from the store feed the summary
+func TestGetStatsSummary(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	s.CreateBook(&store.Book{Title: "One", Author: "A", DateRead: "2023/02/01", Shelf: "read", Pages: 200})
+	s.CreateBook(&store.Book{Title: "Two", Author: "B", DateRead: "2024/03/01", Shelf: "read", Pages: 100})
+	s.CreateBook(&store.Book{Title: "Three", Author: "C", DateRead: "2024/03/05", Shelf: "read", Pages: 300})
+	s.SetGoal(2023, 2)
+	s.SetGoal(2024, 2)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stats/summary", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetStatsSummary(w, req)
+
+	var response struct {
+		Years []struct {
+			Year       int    `json:"year"`
+			Books      int    `json:"books"`
+			GoalStatus string `json:"goalStatus"`
+			BooksDelta int    `json:"booksDelta"`
+		} `json:"years"`
+		Lifetime struct {
+			Books int `json:"books"`
+			Pages int `json:"pages"`
+		} `json:"lifetime"`
+		BestMonth struct {
+			Year  int `json:"year"`
+			Month int `json:"month"`
+		} `json:"bestMonth"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Years) != 2 || response.Years[0].GoalStatus != "missed" || response.Years[1].GoalStatus != "met" {
+		t.Errorf("Unexpected years: %+v", response.Years)
+	}
+	if response.Years[1].BooksDelta != 1 {
+		t.Errorf("Expected 2024 to be up one book, got %d", response.Years[1].BooksDelta)
+	}
+	if response.Lifetime.Books != 3 || response.Lifetime.Pages != 600 {
+		t.Errorf("Unexpected lifetime totals: %+v", response.Lifetime)
+	}
+	if response.BestMonth.Year != 2024 || response.BestMonth.Month != 3 {
+		t.Errorf("Expected best month 2024-03, got %+v", response.BestMonth)
+	}
+}
+
+// TestGetBadgeWithGoal verifies the badge shows progress toward the year's goal
+func TestGetBadgeWithGoal(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	if err := s.SetGoal(2025, 4); err != nil {
+		t.Fatalf("Failed to set goal: %v", err)
+	}
+	if _, err := s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", Pages: 264, DateRead: "2025/02/01", Shelf: "read"}); err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/badge/2025.svg", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetBadge(w, req)
+
+	body := w.Body.Bytes()
+	if !bytes.Contains(body, []byte("1 / 4 books")) {
+		t.Errorf("Expected goal progress in badge: %s", body)
+	}
+	if !bytes.Contains(body, []byte(`width="54.0"`)) {
+		t.Errorf("Expected a quarter-full bar: %s", body)
+	}
+}
+
+// TestGetCalendarPrivate verifies loans appear only with a valid token
+func TestGetCalendarPrivate(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+	os.Setenv("CALENDAR_TOKEN", "calendar-secret")
+	defer os.Unsetenv("CALENDAR_TOKEN")
+
+	id, err := s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read", DateRead: "2025/02/01"})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+	if _, err := s.CreateLoan(&store.Loan{BookID: id, Borrower: "Sam", LentDate: "2025/03/01", DueDate: "2025/04/01"}); err != nil {
+		t.Fatalf("Failed to create loan: %v", err)
+	}
+	if err := s.SetGoal(2025, 30); err != nil {
+		t.Fatalf("Failed to set goal: %v", err)
+	}
+
+	tests := []struct {
+		path      string
+		wantCode  int
+		wantLoans bool
+	}{
+		{"/api/calendar.ics", http.StatusOK, false},
+		{"/api/calendar.ics?token=calendar-secret", http.StatusOK, true},
+		{"/api/calendar.ics?token=wrong", http.StatusUnauthorized, false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+
+		// Execute
+		GetCalendar(w, req)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.wantCode, w.Code)
+			continue
+		}
+		if tt.wantCode != http.StatusOK {
+			continue
+		}
+		body := w.Body.Bytes()
+		if !bytes.Contains(body, []byte("DTSTART;VALUE=DATE:20251231")) || !bytes.Contains(body, []byte("1 of 30 books read in 2025")) {
+			t.Errorf("%s: expected the goal checkpoint", tt.path)
+		}
+		if got := bytes.Contains(body, []byte("due back from Sam")); got != tt.wantLoans {
+			t.Errorf("%s: loan included = %v, want %v", tt.path, got, tt.wantLoans)
+		}
+	}
+}
+
+// TestGetCalendarToken verifies the subscription URL carries the token
+func TestGetCalendarToken(t *testing.T) {
+	os.Setenv("CALENDAR_TOKEN", "calendar secret")
+	defer os.Unsetenv("CALENDAR_TOKEN")
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/api/calendar/token", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetCalendarToken(w, req)
+
+	var response map[string]string
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response["url"] != "http://example.com/api/calendar.ics?token=calendar+secret" {
+		t.Errorf("Unexpected URL: %s", response["url"])
+	}
+}
+
+// TestExportBooksReadCount verifies re-reads survive a Goodreads-style
+// export and import
+func TestExportBooksReadCount(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	id, err := s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", DateRead: "2025/02/01", Shelf: "read"})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+	if _, err := s.AddRead(&store.Read{BookID: id, DateFinished: "2019/06/01"}); err != nil {
+		t.Fatalf("Failed to add read: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	ExportBooks(w, req)
+
+	var exported []books.Book
+	if err := json.NewDecoder(w.Body).Decode(&exported); err != nil {
+		t.Fatalf("Failed to decode export: %v", err)
+	}
+
+	// Import into a fresh database
+	s2, err := store.New(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer s2.Close()
+	if _, err := s2.ImportFromJSON(exported); err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+	all, _ := s2.GetAllBooks()
+	if len(all) != 1 {
+		t.Fatalf("Expected 1 book, got %d", len(all))
+	}
+	reads, _ := s2.GetReads(all[0].ID)
+	if len(reads) != 2 {
+		t.Errorf("Expected 2 reads after the round trip, got %d", len(reads))
+	}
+}
+
+// TestExportBooksCSV verifies the CSV export uses Goodreads' columns
+func TestExportBooksCSV(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	if _, err := s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", ISBN: "0807083690", Pages: 264, DateRead: "2025/02/01", Shelf: "read", Tags: "classics", Format: "ebook", Ownership: "owned", Rating: 5}); err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export?format=csv", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	ExportBooks(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(rows) != 2 || len(rows[0]) != 24 || rows[0][1] != "Title" || rows[0][18] != "Exclusive Shelf" {
+		t.Fatalf("Expected a Goodreads header and one row, got %v", rows)
+	}
+	want := map[int]string{1: "Kindred", 3: "Butler, Octavia E.", 5: `="0807083690"`, 7: "5", 10: "Kindle Edition", 11: "264", 14: "2025/02/01", 16: "read, classics", 18: "read", 22: "1", 23: "1"}
+	for col, v := range want {
+		if rows[1][col] != v {
+			t.Errorf("Expected %s %q, got %q", rows[0][col], v, rows[1][col])
+		}
+	}
+}
+
+// TestExportBooksCSVDNF verifies abandoned books land on a Goodreads exclusive shelf
+func TestExportBooksCSVDNF(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	for _, b := range []store.Book{
+		{Title: "Dawn", Author: "Octavia E. Butler", DateRead: "2020/01/01", Shelf: "dnf", DNFDate: "2025/02/01"},
+		{Title: "Ulysses", Author: "James Joyce", Shelf: "dnf", Tags: "classics", DNFDate: "2025/03/01"},
+	} {
+		b := b
+		if _, err := s.CreateBook(&b); err != nil {
+			t.Fatalf("Failed to create book: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export?format=csv", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	ExportBooks(w, req)
+
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected a header and two rows, got %v", rows)
+	}
+	want := map[string][2]string{
+		"Dawn":    {"did-not-finish", "read"},
+		"Ulysses": {"did-not-finish, classics", "to-read"},
+	}
+	for _, row := range rows[1:] {
+		if w := want[row[1]]; row[16] != w[0] || row[18] != w[1] {
+			t.Errorf("Expected %s on shelves %q and %q, got %q and %q", row[1], w[0], w[1], row[16], row[18])
+		}
+	}
+}
+
+// TestExportBooksFilters verifies shelf and year filters and the native NDJSON format
+func TestExportBooksFilters(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	for _, b := range []store.Book{
+		{Title: "Kindred", Author: "Octavia E. Butler", DateRead: "2025/02/01", Shelf: "read", Tags: "favorites"},
+		{Title: "Dawn", Author: "Octavia E. Butler", DateRead: "2024/06/01", Shelf: "read", Tags: "favorites"},
+		{Title: "Beloved", Author: "Toni Morrison", DateRead: "2025/03/01", Shelf: "read"},
+	} {
+		b := b
+		if _, err := s.CreateBook(&b); err != nil {
+			t.Fatalf("Failed to create book: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export?format=ndjson&shelf=Favorites&year=2025", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	ExportBooks(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Expected NDJSON content type, got %q", ct)
+	}
+	lines := bytes.Split(bytes.TrimSpace(w.Body.Bytes()), []byte("\n"))
+	if len(lines) != 1 {
+		t.Fatalf("Expected one book, got %d: %s", len(lines), w.Body.String())
+	}
+	var book map[string]interface{}
+	if err := json.Unmarshal(lines[0], &book); err != nil {
+		t.Fatalf("Failed to parse line: %v", err)
+	}
+	if book["title"] != "Kindred" || book["tags"] != "favorites" || book["id"] == nil {
+		t.Errorf("Expected Kindred with native fields, got %v", book)
+	}
+}
+
+// TestExportBooksInvalidParams verifies bad format and year values are rejected
+func TestExportBooksInvalidParams(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	for _, query := range []string{"format=xml", "year=last"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/export?"+query, nil)
+		w := httptest.NewRecorder()
+
+		// Execute
+		ExportBooks(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", query, w.Code)
+		}
+	}
+}
+
+// TestImportLibrary verifies a StoryGraph export posted as the raw body is imported
+func TestImportLibrary(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	csvBody := "Title,Authors,ISBN/UID,Format,Read Status,Last Date Read,Star Rating\n" +
+		"Kindred,Octavia E. Butler,9780807083697,paperback,read,2024/06/20,4.25\n" +
+		",Nobody,,,to-read,,\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/import/storygraph", bytes.NewBufferString(csvBody))
+	// curl --data-binary's default content type; the body is still the file
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	// Execute
+	ImportLibrary(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp["imported"] != float64(1) || resp["skipped"] != float64(1) {
+		t.Errorf("Expected 1 imported and 1 skipped, got %v", resp)
+	}
+	all, _ := s.GetAllBooks()
+	if len(all) != 1 || all[0].Rating != 4.25 || all[0].DateRead != "2024/06/20" {
+		t.Errorf("Unexpected imported books: %+v", all)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/import/goodreads-html", bytes.NewBufferString(csvBody))
+	w = httptest.NewRecorder()
+	ImportLibrary(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown source, got %d", w.Code)
+	}
+}
+
+// TestExportBooksBibTeX verifies BibTeX entries, credits and the tag filter
+func TestExportBooksBibTeX(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	for _, b := range []store.Book{
+		{Title: "The Three-Body Problem (Remembrance of Earth's Past, #1)", Author: "Cixin Liu", AdditionalAuthors: "Ken Liu (Translator)",
+			Publisher: "Tor & Forge", YearPublished: 2014, OriginalPublicationYear: 2008, ISBN13: "9780765377067", Shelf: "read", Tags: "cite"},
+		{Title: "Kindred", Author: "Octavia E. Butler", YearPublished: 1979, Shelf: "read"},
+	} {
+		b := b
+		if _, err := s.CreateBook(&b); err != nil {
+			t.Fatalf("Failed to create book: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export?format=bibtex&tag=cite", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	ExportBooks(w, req)
+
+	body := w.Body.String()
+	for _, want := range []string{
+		"@book{liu2014threebody,\n",
+		"  author = {Cixin Liu},\n",
+		"  translator = {Ken Liu},\n",
+		"  title = {The Three-Body Problem},\n",
+		"  series = {Remembrance of Earth's Past},\n",
+		"  number = {1},\n",
+		"  publisher = {Tor \\& Forge},\n",
+		"  year = {2014},\n",
+		"  origdate = {2008},\n",
+		"  isbn = {9780765377067},\n",
+	} {
+		if !bytes.Contains([]byte(body), []byte(want)) {
+			t.Errorf("Expected %q in:\n%s", want, body)
+		}
+	}
+	if bytes.Contains([]byte(body), []byte("Kindred")) {
+		t.Errorf("Expected the tag filter to leave out Kindred:\n%s", body)
+	}
+}
+
+// TestExportBooksCSLJSON verifies CSL-JSON names, dates and citation keys
+func TestExportBooksCSLJSON(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	for _, b := range []store.Book{
+		{Title: "The Dispossessed", Author: "Ursula K. Le Guin", YearPublished: 1974, Shelf: "read"},
+		{Title: "The Dispossessed", Author: "Ursula K. Le Guin", YearPublished: 1974, Shelf: "to-read"},
+	} {
+		b := b
+		if _, err := s.CreateBook(&b); err != nil {
+			t.Fatalf("Failed to create book: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export?format=csl-json&shelf=to-read", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	ExportBooks(w, req)
+
+	var items []struct {
+		ID     string `json:"id"`
+		Type   string `json:"type"`
+		Author []struct {
+			Family string `json:"family"`
+			Given  string `json:"given"`
+		} `json:"author"`
+		Issued struct {
+			DateParts [][]int `json:"date-parts"`
+		} `json:"issued"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&items); err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("Expected 1 item, got %d", len(items))
+	}
+	item := items[0]
+	// The read copy was added first and keeps the plain key even when filtered out
+	if item.ID != "leguin1974dispossessedb" || item.Type != "book" {
+		t.Errorf("Unexpected id or type: %+v", item)
+	}
+	if len(item.Author) != 1 || item.Author[0].Family != "Le Guin" || item.Author[0].Given != "Ursula K." {
+		t.Errorf("Expected the particle kept with the family name, got %+v", item.Author)
+	}
+	if len(item.Issued.DateParts) != 1 || item.Issued.DateParts[0][0] != 1974 {
+		t.Errorf("Unexpected issued date: %+v", item.Issued)
+	}
+}
+
+// TestExportBooksMarkdown verifies the zip of Markdown notes and year indexes
+func TestExportBooksMarkdown(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	id, err := s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", ISBN13: "9780807083697", DateRead: "2024/06/20",
+		Shelf: "read", Rating: 5, Tags: "classics, time-travel", Review: `Gripping.<br/><br/><i>Still</i> thinking &amp; shaken.`})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+	if _, err := s.CreateNote(&store.Note{BookID: id, Kind: store.NoteKindQuote, Text: "I lost an arm on my last trip home.", Page: 9}); err != nil {
+		t.Fatalf("Failed to create note: %v", err)
+	}
+	for _, b := range []store.Book{
+		{Title: "Dawn", Author: "Octavia E. Butler", DateRead: "2024/01/05", Shelf: "read"},
+		{Title: "Dawn", Author: "Someone Else", YearPublished: 2020, Shelf: "to-read"},
+	} {
+		b := b
+		if _, err := s.CreateBook(&b); err != nil {
+			t.Fatalf("Failed to create book: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export?format=markdown", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	ExportBooks(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/zip" {
+		t.Fatalf("Expected a zip, got %q", ct)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatalf("Failed to open zip: %v", err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"Books/Kindred.md", "Books/Dawn (butlernddawn).md", "Books/Dawn (else2020dawn).md", "Years/2024.md"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("Expected %s in zip, got %v", name, len(files))
+		}
+	}
+	note := files["Books/Kindred.md"]
+	for _, want := range []string{
+		"---\ntitle: \"Kindred\"\nauthor: \"Octavia E. Butler\"\nisbn13: \"9780807083697\"\n",
+		"date_read: 2024-06-20\n",
+		"shelf: \"read\"\nrating: 5\n",
+		"tags:\n  - \"classics\"\n  - \"time-travel\"\n---\n",
+		"## Review\n\nGripping.\n\n*Still* thinking & shaken.\n",
+		"> I lost an arm on my last trip home. (p. 9)\n",
+	} {
+		if !bytes.Contains([]byte(note), []byte(want)) {
+			t.Errorf("Expected %q in note:\n%s", want, note)
+		}
+	}
+	index := files["Years/2024.md"]
+	if !bytes.Contains([]byte(index), []byte("books: 2\n")) ||
+		!bytes.Contains([]byte(index), []byte("- 2024-01-05 [[Dawn (butlernddawn)]] by Octavia E. Butler\n- 2024-06-20 [[Kindred]] by Octavia E. Butler ★★★★★\n")) {
+		t.Errorf("Unexpected year index:\n%s", index)
+	}
+}
+
+// TestExportBooksMarkdownYearOrder verifies the year index is ordered by
+// date even when stored dates aren't zero-padded
+func TestExportBooksMarkdownYearOrder(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	for _, b := range []store.Book{
+		{Title: "October", Author: "A", DateRead: "2024/10/1", Shelf: "read"},
+		{Title: "September", Author: "B", DateRead: "2024/9/1", Shelf: "read"},
+	} {
+		b := b
+		if _, err := s.CreateBook(&b); err != nil {
+			t.Fatalf("Failed to create book: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export?format=markdown", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	ExportBooks(w, req)
+
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatalf("Failed to open zip: %v", err)
+	}
+	for _, f := range zr.File {
+		if f.Name != "Years/2024.md" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", f.Name, err)
+		}
+		index, _ := io.ReadAll(rc)
+		rc.Close()
+		if !bytes.Contains(index, []byte("- 2024-09-01 [[September]] by B\n- 2024-10-01 [[October]] by A\n")) {
+			t.Errorf("Unexpected year index:\n%s", index)
+		}
+		return
+	}
+	t.Error("Expected Years/2024.md in zip")
+}
+
+// TestBackupRestore verifies a downloaded backup restores goals and books,
+// and that a damaged upload is refused without changing anything
+func TestBackupRestore(t *testing.T) {
+	// Setup test database
+	s := setupTestStore(t)
+	defer teardownTestStore(t, s)
+
+	if _, err := s.CreateBook(&store.Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"}); err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+	if err := s.SetGoal(2025, 40); err != nil {
+		t.Fatalf("Failed to set goal: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/backup", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	BackupDatabase(w, req)
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/zip" {
+		t.Fatalf("Expected a zip, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	archive := w.Body.Bytes()
+
+	// Replace the library, then restore the backup over it
+	all, _ := s.GetAllBooks()
+	s.DeleteBook(all[0].ID)
+	s.CreateBook(&store.Book{Title: "Dune", Author: "Frank Herbert", Shelf: "read"})
+	s.SetGoal(2025, 10)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, _ := mw.CreateFormFile("file", "backup.zip")
+	part.Write(archive)
+	mw.Close()
+	req = httptest.NewRequest(http.MethodPost, "/api/restore", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w = httptest.NewRecorder()
+	RestoreDatabase(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result struct {
+		Restored bool           `json:"restored"`
+		Tables   map[string]int `json:"tables"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !result.Restored || result.Tables["books"] != 1 || result.Tables["goals"] != 1 {
+		t.Errorf("Unexpected restore result: %+v", result)
+	}
+	all, _ = s.GetAllBooks()
+	if len(all) != 1 || all[0].Title != "Kindred" {
+		t.Errorf("Expected Kindred restored, got %+v", all)
+	}
+	if goal, _ := s.GetGoal(2025); goal == nil || goal.BookTarget != 40 {
+		t.Errorf("Expected goal of 40 restored, got %+v", goal)
+	}
+
+	// A raw body is read as the archive whatever its content type
+	req = httptest.NewRequest(http.MethodPost, "/api/restore", bytes.NewReader(archive))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	RestoreDatabase(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected form-encoded raw body restored, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// A truncated archive is a bad request
+	req = httptest.NewRequest(http.MethodPost, "/api/restore", bytes.NewReader(archive[:len(archive)/2]))
+	w = httptest.NewRecorder()
+	RestoreDatabase(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+	if all, _ = s.GetAllBooks(); len(all) != 1 {
+		t.Errorf("Expected library unchanged, got %d books", len(all))
+	}
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kristenwomack/reading-app/backend/internal/store"
+)
+
+// authorResponse is an author with their reading stats
+type authorResponse struct {
+	ID        int64    `json:"id"`
+	Name      string   `json:"name"`
+	Aliases   []string `json:"aliases,omitempty"`
+	BooksRead int      `json:"booksRead"`
+	Pages     int      `json:"pages"`
+	FirstRead string   `json:"firstRead,omitempty"`
+	LastRead  string   `json:"lastRead,omitempty"`
+}
+
+// toAuthorResponse converts a store.Author for the API
+func toAuthorResponse(a store.Author) authorResponse {
+	return authorResponse{
+		ID:        a.ID,
+		Name:      a.Name,
+		Aliases:   a.Aliases,
+		BooksRead: a.BooksRead,
+		Pages:     a.Pages,
+		FirstRead: a.FirstRead,
+		LastRead:  a.LastRead,
+	}
+}
+
+// GetAuthors handles GET /api/authors
+func GetAuthors(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authors, err := dataStore.GetAuthors()
+	if err != nil {
+		http.Error(w, "Failed to get authors", http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]authorResponse, len(authors))
+	for i, a := range authors {
+		result[i] = toAuthorResponse(a)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"authors": result})
+}
+
+// GetAuthor handles GET /api/authors/{id}
+func GetAuthor(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract ID from path
+	path := strings.TrimPrefix(r.URL.Path, "/api/authors/")
+	id, err := strconv.ParseInt(path, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid author ID", http.StatusBadRequest)
+		return
+	}
+
+	author, err := dataStore.GetAuthor(id)
+	if err != nil {
+		http.Error(w, "Failed to get author", http.StatusInternalServerError)
+		return
+	}
+	if author == nil {
+		http.Error(w, "Author not found", http.StatusNotFound)
+		return
+	}
+
+	authorBooks, err := dataStore.GetAuthorBooks(id)
+	if err != nil {
+		http.Error(w, "Failed to get author books", http.StatusInternalServerError)
+		return
+	}
+
+	type authorBookResponse struct {
+		ID       int64  `json:"id"`
+		Title    string `json:"title"`
+		Role     string `json:"role"`
+		Shelf    string `json:"shelf"`
+		DateRead string `json:"dateRead,omitempty"`
+		Pages    int    `json:"pages"`
+	}
+	bookList := make([]authorBookResponse, len(authorBooks))
+	for i, ab := range authorBooks {
+		bookList[i] = authorBookResponse{
+			ID:       ab.ID,
+			Title:    ab.Title,
+			Role:     ab.Role,
+			Shelf:    ab.Shelf,
+			DateRead: ab.DateRead,
+			Pages:    ab.Pages,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"author": toAuthorResponse(*author),
+		"books":  bookList,
+	})
+}
+
+// MergeAuthors handles POST /api/authors/merge
+func MergeAuthors(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		SourceID int64 `json:"sourceId"`
+		TargetID int64 `json:"targetId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if req.SourceID == req.TargetID {
+		http.Error(w, "Source and target must differ", http.StatusBadRequest)
+		return
+	}
+
+	for _, id := range []int64{req.SourceID, req.TargetID} {
+		author, err := dataStore.GetAuthor(id)
+		if err != nil {
+			http.Error(w, "Failed to get author", http.StatusInternalServerError)
+			return
+		}
+		if author == nil {
+			http.Error(w, "Author not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	if err := dataStore.MergeAuthors(req.SourceID, req.TargetID); err != nil {
+		http.Error(w, "Failed to merge authors", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
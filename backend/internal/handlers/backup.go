@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/kristenwomack/reading-app/backend/internal/store"
+)
+
+// maxBackupSize caps the size of an uploaded backup archive
+const maxBackupSize = 200 << 20
+
+// BackupDatabase handles GET /api/backup, downloading a zip of every table
+// with the schema version and per-table checksums (see store.Backup)
+func BackupDatabase(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Build the archive first so a failure can still be reported
+	var buf bytes.Buffer
+	if err := dataStore.Backup(&buf); err != nil {
+		http.Error(w, "Failed to back up database", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition",
+		"attachment; filename=reading-tracker-backup-"+time.Now().Format("20060102")+".zip")
+	w.Write(buf.Bytes())
+}
+
+// RestoreDatabase handles POST /api/restore, replacing the whole database
+// with a backup archive sent as the raw body or the "file" field of a
+// multipart form. Nothing changes unless the archive is valid.
+func RestoreDatabase(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := uploadedFile(w, r, maxBackupSize)
+	if err != nil {
+		http.Error(w, "Missing file", http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+	archive, err := io.ReadAll(body)
+	if err != nil {
+		http.Error(w, "Backup too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	result, err := dataStore.Restore(archive)
+	if errors.Is(err, store.ErrInvalidBackup) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to restore database", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"restored":      true,
+		"schemaVersion": result.SchemaVersion,
+		"tables":        result.Tables,
+	})
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// badgeTheme holds the colours of a badge
+type badgeTheme struct {
+	background string
+	text       string
+	muted      string
+	track      string
+	bar        string
+	barMet     string
+}
+
+// badgeThemes are the themes selectable with ?theme=
+var badgeThemes = map[string]badgeTheme{
+	"light": {background: "#ffffff", text: "#1f2328", muted: "#656d76", track: "#eaeef2", bar: "#0969da", barMet: "#1a7f37"},
+	"dark":  {background: "#0d1117", text: "#e6edf3", muted: "#8d96a0", track: "#30363d", bar: "#4493f8", barMet: "#3fb950"},
+}
+
+// badgeSizes scale the badge's 240x56 layout with ?size=
+var badgeSizes = map[string]float64{
+	"small":  1,
+	"medium": 1.5,
+	"large":  2,
+}
+
+// badgeMaxAge is how long clients and proxies may cache a badge, in seconds
+const badgeMaxAge = 3600
+
+// renderBadge draws a progress badge for books read against a goal. A goal
+// of 0 draws the count without a bar; pages of -1 leaves pages out.
+func renderBadge(year, read, goal, pages int, theme badgeTheme, scale float64) string {
+	const width, height = 240, 56
+
+	headline := fmt.Sprintf("%d books", read)
+	if goal > 0 {
+		headline = fmt.Sprintf("%d / %d books", read, goal)
+	}
+	if pages >= 0 {
+		headline += fmt.Sprintf(" · %d pages", pages)
+	}
+	label := fmt.Sprintf("%d reading", year)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %d %d" role="img" aria-label="%s: %s">`,
+		width*scale, height*scale, width, height, html.EscapeString(label), html.EscapeString(headline))
+	fmt.Fprintf(&b, `<title>%s: %s</title>`, html.EscapeString(label), html.EscapeString(headline))
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" rx="6" fill="%s"/>`, width, height, theme.background)
+	b.WriteString(`<g font-family="-apple-system,Segoe UI,Helvetica,Arial,sans-serif">`)
+	fmt.Fprintf(&b, `<text x="12" y="20" font-size="11" fill="%s">%s</text>`, theme.muted, html.EscapeString(label))
+	fmt.Fprintf(&b, `<text x="12" y="36" font-size="13" font-weight="600" fill="%s">%s</text>`, theme.text, html.EscapeString(headline))
+	b.WriteString(`</g>`)
+
+	if goal > 0 {
+		progress := float64(read) / float64(goal)
+		if progress > 1 {
+			progress = 1
+		}
+		bar := theme.bar
+		if read >= goal {
+			bar = theme.barMet
+		}
+		const barWidth = width - 24
+		fmt.Fprintf(&b, `<rect x="12" y="44" width="%d" height="6" rx="3" fill="%s"/>`, barWidth, theme.track)
+		if progress > 0 {
+			fmt.Fprintf(&b, `<rect x="12" y="44" width="%.1f" height="6" rx="3" fill="%s"/>`, barWidth*progress, bar)
+		}
+	}
+
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// GetBadge handles GET /api/badge/{year}.svg?theme=light|dark&size=small|medium|large&pages=true
+func GetBadge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	yearStr, found := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/badge/"), ".svg")
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	year, err := strconv.Atoi(yearStr)
+	if err != nil {
+		http.Error(w, "invalid year parameter", http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	themeName := q.Get("theme")
+	if themeName == "" {
+		themeName = "light"
+	}
+	theme, ok := badgeThemes[themeName]
+	if !ok {
+		http.Error(w, "invalid theme parameter", http.StatusBadRequest)
+		return
+	}
+	sizeName := q.Get("size")
+	if sizeName == "" {
+		sizeName = "small"
+	}
+	scale, ok := badgeSizes[sizeName]
+	if !ok {
+		http.Error(w, "invalid size parameter", http.StatusBadRequest)
+		return
+	}
+
+	goal := 0
+	if dataStore != nil {
+		g, err := dataStore.GetGoal(year)
+		if err != nil {
+			http.Error(w, "Failed to get goal", http.StatusInternalServerError)
+			return
+		}
+		if g != nil {
+			goal = g.BookTarget
+		}
+	}
+
+	filtered, _ := books.FilterByYear(getBooks(), year)
+	stats := books.CalculateStatistics(books.FilterByShelf(filtered, "read"), year)
+	pages := -1
+	if q.Get("pages") == "true" {
+		pages = stats.TotalPages
+	}
+
+	svg := renderBadge(year, stats.TotalBooks, goal, pages, theme, scale)
+	etag := fmt.Sprintf(`"%x"`, sha1.Sum([]byte(svg)))
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", badgeMaxAge))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.Write([]byte(svg))
+}
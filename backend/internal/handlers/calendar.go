@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kristenwomack/reading-app/backend/internal/auth"
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// calendarEvent is an all-day VEVENT
+type calendarEvent struct {
+	uid         string
+	date        time.Time
+	summary     string
+	description string
+	category    string
+}
+
+// icsEscape escapes a TEXT value (RFC 5545 section 3.3.11)
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeICSLine writes a content line, folding it at 75 octets without
+// splitting a UTF-8 sequence (RFC 5545 section 3.1)
+func writeICSLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+// renderCalendar writes events as an iCalendar stream
+func renderCalendar(name string, events []calendarEvent, now time.Time) string {
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//Reading Tracker//Calendar//EN")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+	writeICSLine(&b, "X-WR-CALNAME:"+icsEscape(name))
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, "UID:"+e.uid)
+		writeICSLine(&b, "DTSTAMP:"+stamp)
+		writeICSLine(&b, "DTSTART;VALUE=DATE:"+e.date.Format("20060102"))
+		writeICSLine(&b, "DTEND;VALUE=DATE:"+e.date.AddDate(0, 0, 1).Format("20060102"))
+		writeICSLine(&b, "SUMMARY:"+icsEscape(e.summary))
+		if e.description != "" {
+			writeICSLine(&b, "DESCRIPTION:"+icsEscape(e.description))
+		}
+		writeICSLine(&b, "CATEGORIES:"+icsEscape(e.category))
+		writeICSLine(&b, "TRANSP:TRANSPARENT")
+		writeICSLine(&b, "END:VEVENT")
+	}
+	writeICSLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// readingEvents returns an event for every finished read with a full date
+func readingEvents(all []books.Book) []calendarEvent {
+	var events []calendarEvent
+	for _, read := range books.RecentlyFinished(all, "", 0) {
+		book := read.Book
+		d, err := books.ParseDate(book.DateRead)
+		if err != nil || d.Month == 0 || d.Day == 0 {
+			continue
+		}
+		description := ""
+		if pages := book.GetPages(); pages > 0 {
+			description = fmt.Sprintf("%d pages", pages)
+		}
+		events = append(events, calendarEvent{
+			uid:         fmt.Sprintf("read-%s-%s@reading-tracker", bookKey(book), read.Finished.Format("20060102")),
+			date:        read.Finished,
+			summary:     fmt.Sprintf("Finished %s by %s", book.GetTitle(), book.Author),
+			description: description,
+			category:    "Reading",
+		})
+	}
+	return events
+}
+
+// goalEvents returns a checkpoint on the last day of each year with a goal
+func goalEvents(all []books.Book, goals map[int]int) []calendarEvent {
+	var events []calendarEvent
+	for year, target := range goals {
+		if target <= 0 {
+			continue
+		}
+		filtered, _ := books.FilterByYear(all, year)
+		read := len(books.FilterByShelf(filtered, "read"))
+		events = append(events, calendarEvent{
+			uid:         fmt.Sprintf("goal-%d@reading-tracker", year),
+			date:        time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC),
+			summary:     fmt.Sprintf("Reading goal deadline: %d books", target),
+			description: fmt.Sprintf("%d of %d books read in %d", read, target, year),
+			category:    "Goal",
+		})
+	}
+	return events
+}
+
+// GetCalendar handles GET /api/calendar.ics. Finish dates and goal
+// deadlines are public; with ?token= or a login, outstanding loan due
+// dates and borrowers are included too.
+func GetCalendar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	private := auth.IsAuthenticated(r)
+	if token := r.URL.Query().Get("token"); token != "" {
+		if !auth.CheckCalendarToken(token) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		private = true
+	}
+
+	allBooks := getBooks()
+	events := readingEvents(allBooks)
+
+	if dataStore != nil {
+		goals, err := dataStore.GetGoals()
+		if err != nil {
+			http.Error(w, "Failed to get goals", http.StatusInternalServerError)
+			return
+		}
+		events = append(events, goalEvents(allBooks, goals)...)
+
+		if private {
+			loans, err := dataStore.GetLoans(true)
+			if err != nil {
+				http.Error(w, "Failed to get loans", http.StatusInternalServerError)
+				return
+			}
+			for _, l := range loans {
+				due, err := time.Parse("2006/01/02", l.DueDate)
+				if err != nil {
+					continue
+				}
+				events = append(events, calendarEvent{
+					uid:         fmt.Sprintf("loan-%d@reading-tracker", l.ID),
+					date:        due,
+					summary:     fmt.Sprintf("%s due back from %s", l.Title, l.Borrower),
+					description: fmt.Sprintf("Lent to %s on %s", l.Borrower, l.LentDate),
+					category:    "Loan",
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].date.Before(events[j].date) })
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", "inline; filename=reading.ics")
+	if private {
+		w.Header().Set("Cache-Control", "private, no-store")
+	}
+	w.Write([]byte(renderCalendar("Reading", events, time.Now())))
+}
+
+// GetCalendarToken handles GET /api/calendar/token, returning the private
+// calendar's subscription URL
+func GetCalendarToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := auth.CalendarToken()
+	if token == "" {
+		http.Error(w, "Private calendar not configured", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"url": baseURL(r) + "/api/calendar.ics?token=" + url.QueryEscape(token),
+	})
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+	"github.com/kristenwomack/reading-app/backend/internal/store"
+)
+
+// cslName is a CSL-JSON name variable
+type cslName struct {
+	Family string `json:"family"`
+	Given  string `json:"given,omitempty"`
+}
+
+// cslDate is a CSL-JSON date as date parts
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// cslItem is a CSL-JSON bibliography item, as read by Zotero, Pandoc and
+// citeproc
+type cslItem struct {
+	ID               string    `json:"id"`
+	Type             string    `json:"type"`
+	Title            string    `json:"title"`
+	Author           []cslName `json:"author,omitempty"`
+	Editor           []cslName `json:"editor,omitempty"`
+	Translator       []cslName `json:"translator,omitempty"`
+	Illustrator      []cslName `json:"illustrator,omitempty"`
+	Publisher        string    `json:"publisher,omitempty"`
+	Issued           *cslDate  `json:"issued,omitempty"`
+	OriginalDate     *cslDate  `json:"original-date,omitempty"`
+	ISBN             string    `json:"ISBN,omitempty"`
+	CollectionTitle  string    `json:"collection-title,omitempty"`
+	CollectionNumber string    `json:"collection-number,omitempty"`
+	NumberOfPages    int       `json:"number-of-pages,omitempty"`
+}
+
+// bibtexEscape escapes LaTeX special characters in a field value
+func bibtexEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`, "&", `\&`, "%", `\%`,
+		"$", `\$`, "#", `\#`, "_", `\_`, "~", `\textasciitilde{}`, "^", `\textasciicircum{}`,
+	).Replace(s)
+}
+
+// creditsByRole groups a book's credited names by role
+func creditsByRole(b store.Book) map[string][]string {
+	names := make(map[string][]string)
+	for _, c := range books.ParseAuthorCredits(b.Author, b.AdditionalAuthors) {
+		names[c.Role] = append(names[c.Role], c.Name)
+	}
+	return names
+}
+
+// bibtexEntry renders a book as a BibTeX @book entry. translator and
+// origdate are biblatex fields that classic BibTeX ignores.
+func bibtexEntry(rec exportRecord) string {
+	b := rec.book
+	credits := creditsByRole(b)
+	title, _ := books.ParseSeries(b.Title)
+
+	var fields [][2]string
+	add := func(name, value string) {
+		if value != "" {
+			fields = append(fields, [2]string{name, value})
+		}
+	}
+	add("author", strings.Join(credits["author"], " and "))
+	add("editor", strings.Join(credits["editor"], " and "))
+	add("translator", strings.Join(credits["translator"], " and "))
+	add("title", title)
+	add("series", b.Series)
+	if b.Series != "" && b.SeriesPosition > 0 {
+		add("number", strconv.FormatFloat(b.SeriesPosition, 'f', -1, 64))
+	}
+	add("publisher", b.Publisher)
+	if year := b.CitationYear(); year > 0 {
+		add("year", strconv.Itoa(year))
+	}
+	if b.OriginalPublicationYear > 0 && b.OriginalPublicationYear != b.CitationYear() {
+		add("origdate", strconv.Itoa(b.OriginalPublicationYear))
+	}
+	isbn := b.ISBN13
+	if isbn == "" {
+		isbn = b.ISBN
+	}
+	add("isbn", isbn)
+	if b.Pages > 0 {
+		add("pagetotal", strconv.Itoa(b.Pages))
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "@book{%s,\n", rec.book.CitationKey)
+	for _, f := range fields {
+		fmt.Fprintf(&out, "  %s = {%s},\n", f[0], bibtexEscape(f[1]))
+	}
+	out.WriteString("}\n")
+	return out.String()
+}
+
+// cslNames converts names to CSL-JSON name variables
+func cslNames(names []string) []cslName {
+	var result []cslName
+	for _, name := range names {
+		given, family := books.SplitName(name)
+		result = append(result, cslName{Family: family, Given: given})
+	}
+	return result
+}
+
+// toCSLItem converts a book to a CSL-JSON item
+func toCSLItem(rec exportRecord) cslItem {
+	b := rec.book
+	credits := creditsByRole(b)
+	title, _ := books.ParseSeries(b.Title)
+	item := cslItem{
+		ID:              rec.book.CitationKey,
+		Type:            "book",
+		Title:           title,
+		Author:          cslNames(credits["author"]),
+		Editor:          cslNames(credits["editor"]),
+		Translator:      cslNames(credits["translator"]),
+		Illustrator:     cslNames(credits["illustrator"]),
+		Publisher:       b.Publisher,
+		ISBN:            b.ISBN13,
+		CollectionTitle: b.Series,
+		NumberOfPages:   b.Pages,
+	}
+	if item.ISBN == "" {
+		item.ISBN = b.ISBN
+	}
+	if b.Series != "" && b.SeriesPosition > 0 {
+		item.CollectionNumber = strconv.FormatFloat(b.SeriesPosition, 'f', -1, 64)
+	}
+	if year := b.CitationYear(); year > 0 {
+		item.Issued = &cslDate{DateParts: [][]int{{year}}}
+	}
+	if b.OriginalPublicationYear > 0 && b.OriginalPublicationYear != b.CitationYear() {
+		item.OriginalDate = &cslDate{DateParts: [][]int{{b.OriginalPublicationYear}}}
+	}
+	return item
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// maxClippingsSize caps the size of an uploaded My Clippings.txt
+const maxClippingsSize = 20 << 20
+
+// ImportClippings handles POST /api/import/clippings. The file may be sent
+// as the raw request body or as the "file" field of a multipart form.
+func ImportClippings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := uploadedFile(w, r, maxClippingsSize)
+	if err != nil {
+		http.Error(w, "Missing file", http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+
+	clippings, err := books.ParseClippings(body)
+	if err != nil {
+		http.Error(w, "Invalid clippings file", http.StatusBadRequest)
+		return
+	}
+
+	result, err := dataStore.ImportClippings(clippings)
+	if err != nil {
+		http.Error(w, "Failed to import clippings", http.StatusInternalServerError)
+		return
+	}
+
+	unmatched := result.Unmatched
+	if unmatched == nil {
+		unmatched = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"imported":   result.Imported,
+		"duplicates": result.Duplicates,
+		"bookmarks":  result.Bookmarks,
+		"unmatched":  unmatched,
+	})
+}
+
+// uploadedFile returns the file sent with an upload request: the "file"
+// field of a multipart form, or else the raw body whatever its content type,
+// so a curl --data-binary upload isn't parsed as a form and lost
+func uploadedFile(w http.ResponseWriter, r *http.Request, maxSize int64) (io.ReadCloser, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.Body, nil
+	}
+	if err := r.ParseMultipartForm(maxSize); err != nil {
+		return nil, err
+	}
+	file, _, err := r.FormFile("file")
+	return file, err
+}
@@ -0,0 +1,439 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+	"github.com/kristenwomack/reading-app/backend/internal/store"
+)
+
+// exportBook matches the books.json Goodreads-style format for round-trip compatibility
+type exportBook struct {
+	Title                   string       `json:"Title"`
+	Author                  string       `json:"Author"`
+	AdditionalAuthors       string       `json:"Additional Authors"`
+	ISBN                    string       `json:"ISBN"`
+	ISBN13                  string       `json:"ISBN13"`
+	Publisher               string       `json:"Publisher"`
+	Pages                   int          `json:"Number of Pages"`
+	YearPublished           int          `json:"Year Published"`
+	OriginalPublicationYear int          `json:"Original Publication Year"`
+	DateStarted             string       `json:"Date Started,omitempty"`
+	DateRead                string       `json:"Date Read"`
+	DateAdded               string       `json:"Date Added"`
+	Shelf                   string       `json:"Shelf"`
+	Bookshelves             string       `json:"Bookshelves,omitempty"`
+	MyReview                string       `json:"My Review"`
+	CoverURL                string       `json:"CoverURL,omitempty"`
+	Format                  string       `json:"Format,omitempty"`
+	DurationMinutes         int          `json:"Duration Minutes,omitempty"`
+	DNFDate                 string       `json:"DNF Date,omitempty"`
+	DNFPage                 int          `json:"DNF Page,omitempty"`
+	DNFPercent              float64      `json:"DNF Percent,omitempty"`
+	DNFReason               string       `json:"DNF Reason,omitempty"`
+	Ownership               string       `json:"Ownership,omitempty"`
+	OwnedFormat             string       `json:"Owned Format,omitempty"`
+	PurchaseDate            string       `json:"Purchase Date,omitempty"`
+	PurchasePrice           float64      `json:"Purchase Price,omitempty"`
+	ShelfLocation           string       `json:"Shelf Location,omitempty"`
+	Rating                  float64      `json:"My Rating"`
+	ReadCount               int          `json:"Read Count,omitempty"`
+	Notes                   []books.Note `json:"Notes,omitempty"`
+}
+
+// nativeBook is a book in our own field names: the BookRequest accepted by
+// POST /api/books, plus its ID, reads and notes
+type nativeBook struct {
+	ID int64 `json:"id"`
+	BookRequest
+	Reads     []readResponse `json:"reads,omitempty"`
+	Notes     []noteResponse `json:"notes,omitempty"`
+	CreatedAt string         `json:"createdAt"`
+	UpdatedAt string         `json:"updatedAt"`
+}
+
+// goodreadsCSVHeader is the column order of a Goodreads library export, so
+// the CSV can be imported back into Goodreads
+var goodreadsCSVHeader = []string{
+	"Book Id", "Title", "Author", "Author l-f", "Additional Authors", "ISBN", "ISBN13",
+	"My Rating", "Average Rating", "Publisher", "Binding", "Number of Pages", "Year Published",
+	"Original Publication Year", "Date Read", "Date Added", "Bookshelves",
+	"Bookshelves with positions", "Exclusive Shelf", "My Review", "Spoiler", "Private Notes",
+	"Read Count", "Owned Copies",
+}
+
+// bindings are Goodreads binding names for our formats
+var bindings = map[string]string{
+	books.FormatPrint:     "Paperback",
+	books.FormatEbook:     "Kindle Edition",
+	books.FormatAudiobook: "Audible Audio",
+}
+
+// exportRecord is a stored book with its reads and notes
+type exportRecord struct {
+	book  store.Book
+	reads []store.Read
+	notes []store.Note
+}
+
+// authorLastFirst writes a name surname first for Goodreads' "Author l-f"
+// column, taking the last word as the surname as Goodreads does
+func authorLastFirst(name string) string {
+	name = strings.TrimSpace(name)
+	i := strings.LastIndex(name, " ")
+	if i < 0 {
+		return name
+	}
+	return name[i+1:] + ", " + name[:i]
+}
+
+// finishedIn reports whether any read of a book finished in year
+func finishedIn(rec exportRecord, year int) bool {
+	for _, d := range finishDates(rec) {
+		if parsed, err := books.ParseDate(d); err == nil && parsed.Year == year {
+			return true
+		}
+	}
+	return false
+}
+
+// onShelf reports whether a book is on shelf or carries it as a tag
+func onShelf(b store.Book, shelf string) bool {
+	return books.NormalizeShelf(b.Shelf) == shelf || hasTag(b, shelf)
+}
+
+// hasTag reports whether a book carries a tag
+func hasTag(b store.Book, tag string) bool {
+	for _, t := range books.ParseTags(b.Tags) {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// loadExportRecords reads every book with its reads and notes, keeping
+// those on shelf, carrying tag (when set) and finished in year (when
+// non-zero)
+func loadExportRecords(shelf, tag string, year int) ([]exportRecord, error) {
+	allBooks, err := dataStore.GetAllBooks()
+	if err != nil {
+		return nil, err
+	}
+	reads, err := dataStore.GetAllReads()
+	if err != nil {
+		return nil, err
+	}
+	notes, err := dataStore.GetAllNotes()
+	if err != nil {
+		return nil, err
+	}
+
+	shelf = books.NormalizeShelf(shelf)
+	tag = books.NormalizeShelf(tag)
+	var records []exportRecord
+	for _, b := range allBooks {
+		rec := exportRecord{book: b, reads: reads[b.ID], notes: notes[b.ID]}
+		if shelf != "" && !onShelf(b, shelf) {
+			continue
+		}
+		if tag != "" && !hasTag(b, tag) {
+			continue
+		}
+		if year != 0 && !finishedIn(rec, year) {
+			continue
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// readCount is how many times the book was read, counting a date read
+// with no recorded reads as one
+func readCount(rec exportRecord) int {
+	if len(rec.reads) == 0 && rec.book.DateRead != "" {
+		return 1
+	}
+	return len(rec.reads)
+}
+
+// toExportBook converts a record to the Goodreads-style JSON
+func toExportBook(rec exportRecord) exportBook {
+	b := rec.book
+	e := exportBook{
+		Title:                   b.Title,
+		Author:                  b.Author,
+		AdditionalAuthors:       b.AdditionalAuthors,
+		ISBN:                    b.ISBN,
+		ISBN13:                  b.ISBN13,
+		Publisher:               b.Publisher,
+		Pages:                   b.Pages,
+		YearPublished:           b.YearPublished,
+		OriginalPublicationYear: b.OriginalPublicationYear,
+		DateStarted:             b.DateStarted,
+		DateRead:                b.DateRead,
+		DateAdded:               b.DateAdded,
+		Shelf:                   b.Shelf,
+		Bookshelves:             books.JoinShelves(b.Shelf, b.Tags),
+		MyReview:                b.Review,
+		CoverURL:                b.CoverURL,
+		Format:                  b.Format,
+		DurationMinutes:         b.DurationMinutes,
+		DNFDate:                 b.DNFDate,
+		DNFPage:                 b.DNFPage,
+		DNFPercent:              b.DNFPercent,
+		DNFReason:               b.DNFReason,
+		Ownership:               b.Ownership,
+		OwnedFormat:             b.OwnedFormat,
+		PurchaseDate:            b.PurchaseDate,
+		PurchasePrice:           b.PurchasePrice,
+		ShelfLocation:           b.ShelfLocation,
+		Rating:                  b.Rating,
+		ReadCount:               readCount(rec),
+	}
+	for _, n := range rec.notes {
+		e.Notes = append(e.Notes, books.Note{
+			Kind:     n.Kind,
+			Text:     n.Text,
+			Page:     n.Page,
+			Location: n.Location,
+		})
+	}
+	return e
+}
+
+// toNativeBook converts a record to our own field names
+func toNativeBook(rec exportRecord) nativeBook {
+	b := rec.book
+	n := nativeBook{
+		ID: b.ID,
+		BookRequest: BookRequest{
+			Title:                   b.Title,
+			Author:                  b.Author,
+			AdditionalAuthors:       b.AdditionalAuthors,
+			ISBN:                    b.ISBN,
+			ISBN13:                  b.ISBN13,
+			Publisher:               b.Publisher,
+			Pages:                   b.Pages,
+			YearPublished:           b.YearPublished,
+			OriginalPublicationYear: b.OriginalPublicationYear,
+			DateStarted:             b.DateStarted,
+			DateRead:                b.DateRead,
+			DateAdded:               b.DateAdded,
+			Shelf:                   b.Shelf,
+			Review:                  b.Review,
+			CoverURL:                b.CoverURL,
+			Series:                  b.Series,
+			SeriesPosition:          b.SeriesPosition,
+			Format:                  b.Format,
+			DurationMinutes:         b.DurationMinutes,
+			DNFDate:                 b.DNFDate,
+			DNFPage:                 b.DNFPage,
+			DNFPercent:              b.DNFPercent,
+			DNFReason:               b.DNFReason,
+			Ownership:               b.Ownership,
+			OwnedFormat:             b.OwnedFormat,
+			PurchaseDate:            b.PurchaseDate,
+			PurchasePrice:           b.PurchasePrice,
+			ShelfLocation:           b.ShelfLocation,
+			Tags:                    b.Tags,
+			Rating:                  b.Rating,
+		},
+		CreatedAt: b.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: b.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	for _, rd := range rec.reads {
+		n.Reads = append(n.Reads, readResponse{
+			ID:           rd.ID,
+			DateStarted:  rd.DateStarted,
+			DateFinished: rd.DateFinished,
+			Format:       rd.Format,
+			Notes:        rd.Notes,
+		})
+	}
+	for _, note := range rec.notes {
+		n.Notes = append(n.Notes, toNoteResponse(note))
+	}
+	return n
+}
+
+// goodreadsCSVRow converts a record to a row under goodreadsCSVHeader.
+// ISBNs are written ="..." as Goodreads does, keeping leading zeros when
+// the file is opened in a spreadsheet. Goodreads has no dnf exclusive
+// shelf, so an abandoned book goes on read if it was ever finished and
+// to-read otherwise, with did-not-finish among its bookshelves.
+func goodreadsCSVRow(rec exportRecord) []string {
+	b := rec.book
+	itoa := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+	owned := "0"
+	if b.Ownership == "owned" {
+		owned = "1"
+	}
+	format := b.OwnedFormat
+	if format == "" {
+		format = b.Format
+	}
+	shelf, bookshelves := b.Shelf, books.JoinShelves(b.Shelf, b.Tags)
+	if shelf == books.ShelfDNF {
+		shelf = "to-read"
+		if b.DateRead != "" {
+			shelf = "read"
+		}
+		for _, rd := range rec.reads {
+			if rd.DateFinished != "" {
+				shelf = "read"
+			}
+		}
+		bookshelves = strings.Join(append([]string{"did-not-finish"}, books.ParseTags(b.Tags)...), ", ")
+	}
+	return []string{
+		strconv.FormatInt(b.ID, 10),
+		b.Title,
+		b.Author,
+		authorLastFirst(b.Author),
+		b.AdditionalAuthors,
+		`="` + b.ISBN + `"`,
+		`="` + b.ISBN13 + `"`,
+		strconv.Itoa(books.WholeStars(b.Rating)),
+		"",
+		b.Publisher,
+		bindings[books.NormalizeFormat(format)],
+		itoa(b.Pages),
+		itoa(b.YearPublished),
+		itoa(b.OriginalPublicationYear),
+		b.DateRead,
+		b.DateAdded,
+		bookshelves,
+		"",
+		shelf,
+		b.Review,
+		"",
+		"",
+		strconv.Itoa(readCount(rec)),
+		owned,
+	}
+}
+
+// ExportBooks handles GET /api/export?format=goodreads|csv|json|ndjson|bibtex|csl-json|markdown&shelf=&tag=&year=.
+// The default goodreads format is the books.json the server imports from;
+// csv uses Goodreads' export columns; json and ndjson use our own fields,
+// ndjson with one book per line; bibtex and csl-json are citations;
+// markdown is a zip of notes for Obsidian.
+func ExportBooks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	format := q.Get("format")
+	switch format {
+	case "":
+		format = "goodreads"
+	case "goodreads", "csv", "json", "ndjson", "bibtex", "csl-json", "markdown":
+	default:
+		http.Error(w, "invalid format parameter", http.StatusBadRequest)
+		return
+	}
+	year := 0
+	if s := q.Get("year"); s != "" {
+		y, err := strconv.Atoi(s)
+		if err != nil {
+			http.Error(w, "invalid year parameter", http.StatusBadRequest)
+			return
+		}
+		year = y
+	}
+
+	records, err := loadExportRecords(q.Get("shelf"), q.Get("tag"), year)
+	if err != nil {
+		http.Error(w, "Failed to get books", http.StatusInternalServerError)
+		return
+	}
+
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", "attachment; filename=goodreads_library_export.csv")
+		cw := csv.NewWriter(w)
+		cw.Write(goodreadsCSVHeader)
+		for _, rec := range records {
+			cw.Write(goodreadsCSVRow(rec))
+		}
+		cw.Flush()
+
+	case "ndjson":
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", "attachment; filename=books.ndjson")
+		enc := json.NewEncoder(w)
+		for _, rec := range records {
+			if err := enc.Encode(toNativeBook(rec)); err != nil {
+				return
+			}
+		}
+
+	case "bibtex":
+		w.Header().Set("Content-Type", "application/x-bibtex; charset=utf-8")
+		w.Header().Set("Content-Disposition", "attachment; filename=books.bib")
+		for i, rec := range records {
+			if i > 0 {
+				w.Write([]byte("\n"))
+			}
+			w.Write([]byte(bibtexEntry(rec)))
+		}
+
+	case "csl-json":
+		items := make([]cslItem, len(records))
+		for i, rec := range records {
+			items[i] = toCSLItem(rec)
+		}
+		w.Header().Set("Content-Type", "application/vnd.citationstyles.csl+json")
+		w.Header().Set("Content-Disposition", "attachment; filename=books.csl.json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(items)
+
+	case "markdown":
+		// Build the archive first so a failure can still be reported
+		var buf bytes.Buffer
+		if err := writeMarkdownZip(&buf, records); err != nil {
+			http.Error(w, "Failed to export books", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", "attachment; filename=books-markdown.zip")
+		w.Write(buf.Bytes())
+
+	case "json":
+		exported := make([]nativeBook, len(records))
+		for i, rec := range records {
+			exported[i] = toNativeBook(rec)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", "attachment; filename=books.json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(exported)
+
+	default:
+		exported := make([]exportBook, len(records))
+		for i, rec := range records {
+			exported[i] = toExportBook(rec)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", "attachment; filename=books.json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(exported)
+	}
+}
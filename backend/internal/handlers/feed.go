@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// feedTitle names the feeds of recently finished books
+const feedTitle = "Reading Tracker: recently finished"
+
+// excerptLength is the most characters of a review shown in a feed entry
+const excerptLength = 280
+
+// htmlTag matches markup in Goodreads reviews, which may contain <br/>
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// atomFeed is an Atom 1.0 feed (RFC 4287)
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// atomLink is an Atom link element
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// atomEntry is one finished book in an Atom feed
+type atomEntry struct {
+	Title   string     `xml:"title"`
+	ID      string     `xml:"id"`
+	Updated string     `xml:"updated"`
+	Author  atomPerson `xml:"author"`
+	Links   []atomLink `xml:"link"`
+	Summary string     `xml:"summary,omitempty"`
+	Content atomText   `xml:"content"`
+}
+
+// atomPerson names an author
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+// atomText is text content with its type
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// rssFeed is an RSS 2.0 document
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+// rssChannel is the RSS channel of finished books
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+// rssItem is one finished book in an RSS feed
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+// rssGUID identifies an item without being a link to it
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// feedEntry is a finished read prepared for either feed format
+type feedEntry struct {
+	title    string
+	author   string
+	id       string
+	link     string
+	finished time.Time
+	excerpt  string
+	content  string // HTML
+}
+
+// baseURL returns the scheme and host the request was made to, honouring
+// a proxy's X-Forwarded-Proto
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// bookKey identifies a book in feed and calendar IDs: its ID, or a hash of
+// title and author for books loaded from JSON
+func bookKey(b books.Book) string {
+	if b.ID != 0 {
+		return strconv.FormatInt(b.ID, 10)
+	}
+	return fmt.Sprintf("%x", sha1.Sum([]byte(b.GetTitle()+"\x00"+b.Author)))
+}
+
+// excerpt returns plain text from a review, cut at a word boundary
+func excerpt(review string, length int) string {
+	text := strings.Join(strings.Fields(html.UnescapeString(htmlTag.ReplaceAllString(review, " "))), " ")
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	cut := string(runes[:length])
+	if i := strings.LastIndex(cut, " "); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+// feedEntries builds entries for the latest finished reads. IDs are tag
+// URIs (RFC 4151) minted under host.
+func feedEntries(reads []books.FinishedRead, base, host string) []feedEntry {
+	entries := make([]feedEntry, len(reads))
+	for i, read := range reads {
+		book := read.Book
+		e := feedEntry{
+			title:    book.GetTitle(),
+			author:   book.Author,
+			id:       fmt.Sprintf("tag:%s,%s:book/%s", host, read.Finished.Format("2006-01-02"), bookKey(book)),
+			link:     fmt.Sprintf("%s/?year=%d", base, read.Finished.Year()),
+			finished: read.Finished,
+		}
+		if review, ok := book.MyReview.(string); ok {
+			e.excerpt = excerpt(review, excerptLength)
+		}
+
+		var content strings.Builder
+		if cover := getCoverURL(book); cover != "" {
+			fmt.Fprintf(&content, `<p><img src="%s" alt="Cover of %s"></p>`, html.EscapeString(cover), html.EscapeString(e.title))
+		}
+		fmt.Fprintf(&content, "<p><strong>%s</strong> by %s, finished %s.</p>",
+			html.EscapeString(e.title), html.EscapeString(e.author), read.Finished.Format("January 2, 2006"))
+		if e.excerpt != "" {
+			fmt.Fprintf(&content, "<blockquote>%s</blockquote>", html.EscapeString(e.excerpt))
+		}
+		e.content = content.String()
+		entries[i] = e
+	}
+	return entries
+}
+
+// GetFeed handles GET /feed.atom and /feed.rss?shelf=&limit=
+func GetFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 20
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			http.Error(w, "invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+		limit = min(n, 100)
+	}
+
+	base := baseURL(r)
+	host := r.Host
+	if h, _, err := net.SplitHostPort(r.Host); err == nil {
+		host = h
+	}
+	reads := books.RecentlyFinished(getBooks(), r.URL.Query().Get("shelf"), limit)
+	entries := feedEntries(reads, base, host)
+
+	// The newest finish dates the feed
+	var updated time.Time
+	if len(entries) > 0 {
+		updated = entries[0].finished
+	}
+
+	var doc interface{}
+	contentType := "application/atom+xml; charset=utf-8"
+	if strings.HasSuffix(r.URL.Path, ".rss") {
+		contentType = "application/rss+xml; charset=utf-8"
+		channel := rssChannel{
+			Title:       feedTitle,
+			Link:        base + "/",
+			Description: "Books we finished most recently",
+		}
+		if !updated.IsZero() {
+			channel.LastBuildDate = updated.Format(time.RFC1123Z)
+		}
+		for _, e := range entries {
+			channel.Items = append(channel.Items, rssItem{
+				Title:       e.title + " by " + e.author,
+				Link:        e.link,
+				GUID:        rssGUID{Value: e.id},
+				PubDate:     e.finished.Format(time.RFC1123Z),
+				Description: e.content,
+			})
+		}
+		doc = rssFeed{Version: "2.0", Channel: channel}
+	} else {
+		feed := atomFeed{
+			Title:   feedTitle,
+			ID:      base + "/feed.atom",
+			Updated: updated.Format(time.RFC3339),
+			Links: []atomLink{
+				{Href: base + r.URL.RequestURI(), Rel: "self", Type: "application/atom+xml"},
+				{Href: base + "/", Rel: "alternate", Type: "text/html"},
+			},
+		}
+		if updated.IsZero() {
+			feed.Updated = time.Unix(0, 0).UTC().Format(time.RFC3339)
+		}
+		for _, e := range entries {
+			feed.Entries = append(feed.Entries, atomEntry{
+				Title:   e.title,
+				ID:      e.id,
+				Updated: e.finished.Format(time.RFC3339),
+				Author:  atomPerson{Name: e.author},
+				Links:   []atomLink{{Href: e.link, Rel: "alternate", Type: "text/html"}},
+				Summary: e.excerpt,
+				Content: atomText{Type: "html", Body: e.content},
+			})
+		}
+		doc = feed
+	}
+
+	var body bytes.Buffer
+	body.WriteString(xml.Header)
+	enc := xml.NewEncoder(&body)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		http.Error(w, "Failed to render feed", http.StatusInternalServerError)
+		return
+	}
+
+	// ServeContent answers If-None-Match with 304. There's no Last-Modified:
+	// backdated finishes, edited reviews and deletions don't move any date,
+	// so only the ETag tells whether the feed changed.
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", fmt.Sprintf(`"%x"`, sha1.Sum(body.Bytes())))
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body.Bytes()))
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/kristenwomack/reading-app/backend/internal/auth"
 	"github.com/kristenwomack/reading-app/backend/internal/books"
 	"github.com/kristenwomack/reading-app/backend/internal/store"
 )
@@ -21,7 +22,13 @@ func getBooks() []books.Book {
 	if dataStore != nil {
 		storeBooks, err := dataStore.GetAllBooks()
 		if err == nil && len(storeBooks) > 0 {
-			return convertStoreBooks(storeBooks)
+			result := convertStoreBooks(storeBooks)
+			if reads, err := dataStore.GetAllReads(); err == nil {
+				for i := range result {
+					result[i].Reads = convertStoreReads(reads[result[i].ID])
+				}
+			}
+			return result
 		}
 	}
 	return cachedBooks
@@ -32,6 +39,7 @@ func convertStoreBooks(storeBooks []store.Book) []books.Book {
 	result := make([]books.Book, len(storeBooks))
 	for i, sb := range storeBooks {
 		result[i] = books.Book{
+			ID:                      sb.ID,
 			Title:                   sb.Title,
 			Author:                  sb.Author,
 			AdditionalAuthors:       sb.AdditionalAuthors,
@@ -41,32 +49,62 @@ func convertStoreBooks(storeBooks []store.Book) []books.Book {
 			Pages:                   sb.Pages,
 			YearPublished:           sb.YearPublished,
 			OriginalPublicationYear: sb.OriginalPublicationYear,
+			DateStarted:             sb.DateStarted,
 			DateRead:                sb.DateRead,
 			DateAdded:               sb.DateAdded,
-			Bookshelves:             sb.Shelf,
+			Bookshelves:             books.JoinShelves(sb.Shelf, sb.Tags),
 			Shelf:                   sb.Shelf,
 			MyReview:                sb.Review,
 			CoverURL:                sb.CoverURL,
+			Series:                  sb.Series,
+			SeriesPosition:          sb.SeriesPosition,
+			Format:                  sb.Format,
+			DurationMinutes:         sb.DurationMinutes,
+			DNFDate:                 sb.DNFDate,
+			DNFPage:                 sb.DNFPage,
+			DNFPercent:              sb.DNFPercent,
+			DNFReason:               sb.DNFReason,
+			Ownership:               sb.Ownership,
+			OwnedFormat:             sb.OwnedFormat,
+			PurchaseDate:            sb.PurchaseDate,
+			PurchasePrice:           sb.PurchasePrice,
+			ShelfLocation:           sb.ShelfLocation,
+			Rating:                  sb.Rating,
 		}
 	}
 	return result
 }
 
-// GetYears returns available years with book counts
+// convertStoreReads converts store.Read slice to books.Read slice
+func convertStoreReads(storeReads []store.Read) []books.Read {
+	if len(storeReads) == 0 {
+		return nil
+	}
+	result := make([]books.Read, len(storeReads))
+	for i, sr := range storeReads {
+		result[i] = books.Read{
+			DateStarted:  sr.DateStarted,
+			DateFinished: sr.DateFinished,
+			Format:       sr.Format,
+			Notes:        sr.Notes,
+		}
+	}
+	return result
+}
+
+// GetYears returns available years with counts of finished reads
 func GetYears(w http.ResponseWriter, r *http.Request) {
 	yearCounts := make(map[int]int)
 	
 	for _, book := range getBooks() {
-		if book.DateRead == "" || book.Shelf != "read" {
-			continue
-		}
-		
-		date, err := books.ParseDate(book.DateRead)
-		if err != nil {
-			continue
+		for _, dateRead := range book.ReadDates() {
+			date, err := books.ParseDate(dateRead)
+			if err != nil {
+				continue
+			}
+			
+			yearCounts[date.Year]++
 		}
-		
-		yearCounts[date.Year]++
 	}
 	
 	type YearInfo struct {
@@ -87,16 +125,37 @@ func GetYears(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-// GetBooks returns books for a specific year
+// GetBooks returns books for a specific year. The year may be left out when
+// filtering by ownership, owned format or shelf location.
 func GetBooks(w http.ResponseWriter, r *http.Request) {
+	ownershipFilter := books.OwnershipFilter{
+		Ownership: r.URL.Query().Get("ownership"),
+		Format:    r.URL.Query().Get("ownedFormat"),
+		Location:  r.URL.Query().Get("location"),
+	}
+	if ownershipFilter.Ownership != "" && books.NormalizeOwnership(ownershipFilter.Ownership) == "" {
+		http.Error(w, "invalid ownership parameter", http.StatusBadRequest)
+		return
+	}
+	if ownershipFilter.Format != "" && books.NormalizeFormat(ownershipFilter.Format) == "" {
+		http.Error(w, "invalid ownedFormat parameter", http.StatusBadRequest)
+		return
+	}
+	// Purchases and shelf locations are private to the owner
+	private := auth.IsAuthenticated(r)
+	if ownershipFilter.Location != "" && !private {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	yearStr := r.URL.Query().Get("year")
-	if yearStr == "" {
+	if yearStr == "" && ownershipFilter.IsZero() {
 		http.Error(w, "year parameter required", http.StatusBadRequest)
 		return
 	}
 	
 	year, err := strconv.Atoi(yearStr)
-	if err != nil {
+	if yearStr != "" && err != nil {
 		http.Error(w, "invalid year parameter", http.StatusBadRequest)
 		return
 	}
@@ -109,8 +168,10 @@ func GetBooks(w http.ResponseWriter, r *http.Request) {
 		monthFilter, _ = strconv.Atoi(monthStr)
 	}
 	
-	allBooks := getBooks()
-	filtered, _ := books.FilterByYear(allBooks, year)
+	filtered := books.FilterByOwnership(getBooks(), ownershipFilter)
+	if yearStr != "" {
+		filtered, _ = books.FilterByYear(filtered, year)
+	}
 	
 	// Filter by shelf if specified, otherwise return all shelves
 	var booksToReturn []books.Book
@@ -121,45 +182,74 @@ func GetBooks(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	type BookResponse struct {
-		ID       int64  `json:"id,omitempty"`
-		Title    string `json:"title"`
-		Author   string `json:"author"`
-		DateRead string `json:"dateRead"`
-		Pages    int    `json:"pages"`
-		Month    int    `json:"month"`
-		Shelf    string `json:"shelf"`
-		ISBN     string `json:"isbn,omitempty"`
-		CoverURL string `json:"coverUrl,omitempty"`
+		ID        int64    `json:"id,omitempty"`
+		Title     string   `json:"title"`
+		Author    string   `json:"author"`
+		DateRead  string   `json:"dateRead"`
+		Pages     int      `json:"pages"`
+		Month     int      `json:"month"`
+		Shelf     string   `json:"shelf"`
+		ISBN      string   `json:"isbn,omitempty"`
+		CoverURL  string   `json:"coverUrl,omitempty"`
+		Series    string   `json:"series,omitempty"`
+		ReadCount int      `json:"readCount,omitempty"`
+		Format    string   `json:"format,omitempty"`
+		Duration  int      `json:"durationMinutes,omitempty"`
+		DNFDate   string   `json:"dnfDate,omitempty"`
+		DNFPage   int      `json:"dnfPage,omitempty"`
+		DNFPct    float64  `json:"dnfPercent,omitempty"`
+		DNFReason string   `json:"dnfReason,omitempty"`
+		Ownership string   `json:"ownership,omitempty"`
+		OwnedFmt  string   `json:"ownedFormat,omitempty"`
+		Purchased string   `json:"purchaseDate,omitempty"`
+		Price     float64  `json:"purchasePrice,omitempty"`
+		Location  string   `json:"shelfLocation,omitempty"`
+		Tags      []string `json:"tags,omitempty"`
+		Rating    float64  `json:"rating,omitempty"`
 	}
 	
 	var responseBooks []BookResponse
 	for _, book := range booksToReturn {
-		date, _ := books.ParseDate(book.DateRead)
+		dateStr := book.DateRead
+		if dateStr == "" {
+			dateStr = book.DNFDate
+		}
+		date, _ := books.ParseDate(dateStr)
 		
 		// Filter by month if specified
 		if monthFilter > 0 && date.Month != monthFilter {
 			continue
 		}
 		
-		// Use stored cover URL if available, otherwise generate from ISBN
-		coverURL := book.CoverURL
-		if coverURL == "" {
-			isbn := getISBN(book)
-			if isbn != "" {
-				coverURL = "https://covers.openlibrary.org/b/isbn/" + isbn + "-M.jpg"
-			}
-		}
-		
 		responseBooks = append(responseBooks, BookResponse{
-			Title:    book.GetTitle(),
-			Author:   book.Author,
-			DateRead: book.DateRead,
-			Pages:    book.GetPages(),
-			Month:    date.Month,
-			Shelf:    book.Shelf,
-			ISBN:     getISBN(book),
-			CoverURL: coverURL,
+			ID:        book.ID,
+			Title:     book.GetTitle(),
+			Author:    book.Author,
+			DateRead:  book.DateRead,
+			Pages:     book.GetPages(),
+			Month:     date.Month,
+			Shelf:     book.Shelf,
+			ISBN:      getISBN(book),
+			CoverURL:  getCoverURL(book),
+			Series:    book.Series,
+			ReadCount: book.ReadCount(),
+			Format:    book.GetFormat(),
+			Duration:  book.GetDurationMinutes(),
+			DNFDate:   book.DNFDate,
+			DNFPage:   book.GetDNFPage(),
+			DNFPct:    book.GetDNFPercent(),
+			DNFReason: book.DNFReason,
+			Ownership: book.GetOwnership(),
+			OwnedFmt:  book.OwnedFormat,
+			Tags:      book.GetTags(),
+			Rating:    book.GetRating(),
 		})
+		if private {
+			last := &responseBooks[len(responseBooks)-1]
+			last.Purchased = book.PurchaseDate
+			last.Price = book.GetPurchasePrice()
+			last.Location = book.ShelfLocation
+		}
 	}
 	
 	response := map[string]interface{}{
@@ -184,6 +274,17 @@ func getISBN(book books.Book) string {
 	return ""
 }
 
+// getCoverURL returns the stored cover URL, otherwise one generated from the ISBN
+func getCoverURL(book books.Book) string {
+	if book.CoverURL != "" {
+		return book.CoverURL
+	}
+	if isbn := getISBN(book); isbn != "" {
+		return "https://covers.openlibrary.org/b/isbn/" + isbn + "-M.jpg"
+	}
+	return ""
+}
+
 // GetStats returns statistics for a specific year
 func GetStats(w http.ResponseWriter, r *http.Request) {
 	yearStr := r.URL.Query().Get("year")
@@ -198,18 +299,145 @@ func GetStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	filtered, _ := books.FilterByYear(getBooks(), year)
+	allBooks := getBooks()
+	filtered, _ := books.FilterByYear(allBooks, year)
 	readBooks := books.FilterByShelf(filtered, "read")
 	
 	stats := books.CalculateStatistics(readBooks, year)
 	breakdown := books.CalculateMonthlyBreakdown(readBooks)
+	formats := books.CalculateFormatBreakdown(readBooks)
+	
+	// Optionally count audiobook listening time toward pages
+	pageEquivalent := 0
+	if r.URL.Query().Get("pageEquivalent") == "true" {
+		minutesPerPage := books.DefaultMinutesPerPage
+		if mpp, err := strconv.ParseFloat(r.URL.Query().Get("minutesPerPage"), 64); err == nil && mpp > 0 {
+			minutesPerPage = mpp
+		}
+		pageEquivalent = books.PageEquivalent(readBooks, minutesPerPage)
+		stats.TotalPages += pageEquivalent
+	}
+	
+	type formatResponse struct {
+		Format  string `json:"format"`
+		Books   int    `json:"books"`
+		Pages   int    `json:"pages"`
+		Minutes int    `json:"minutes"`
+	}
+	formatList := make([]formatResponse, len(formats))
+	for i, f := range formats {
+		name := f.Format
+		if name == "" {
+			name = "unknown"
+		}
+		formatList[i] = formatResponse{Format: name, Books: f.Books, Pages: f.Pages, Minutes: f.Minutes}
+	}
+	
+	finish := books.CalculateFinishStats(books.FilterByShelf(allBooks, "read"), year)
+	dnf := books.CalculateDNFStats(allBooks, year, stats.TotalBooks)
+	
+	type reasonResponse struct {
+		Reason string `json:"reason"`
+		Count  int    `json:"count"`
+	}
+	reasons := make([]reasonResponse, len(dnf.Reasons))
+	for i, rc := range dnf.Reasons {
+		reasons[i] = reasonResponse{Reason: rc.Reason, Count: rc.Count}
+	}
+	
+	type longestRead struct {
+		Title       string `json:"title"`
+		Author      string `json:"author"`
+		DateStarted string `json:"dateStarted"`
+		DateRead    string `json:"dateRead"`
+		Days        int    `json:"days"`
+	}
+	longest := make([]longestRead, len(finish.Longest))
+	for i, span := range finish.Longest {
+		longest[i] = longestRead{
+			Title:       span.Book.GetTitle(),
+			Author:      span.Book.Author,
+			DateStarted: span.Started.Format("2006/01/02"),
+			DateRead:    span.Finished.Format("2006/01/02"),
+			Days:        span.Days,
+		}
+	}
+	
+	type bookLength struct {
+		Title  string `json:"title"`
+		Author string `json:"author"`
+		Pages  int    `json:"pages"`
+	}
+	toBookLength := func(b *books.Book) *bookLength {
+		if b == nil {
+			return nil
+		}
+		return &bookLength{Title: b.GetTitle(), Author: b.Author, Pages: b.GetPages()}
+	}
+	
+	type bucketResponse struct {
+		Label string `json:"label"`
+		Min   int    `json:"min"`
+		Max   int    `json:"max,omitempty"`
+		Count int    `json:"count"`
+	}
+	histogram := make([]bucketResponse, len(stats.PageHistogram))
+	for i, b := range stats.PageHistogram {
+		histogram[i] = bucketResponse{Label: b.Label, Min: b.Min, Max: b.Max, Count: b.Count}
+	}
+	
+	type authorResponse struct {
+		Author string `json:"author"`
+		Books  int    `json:"books"`
+		Pages  int    `json:"pages"`
+	}
+	topAuthors := make([]authorResponse, len(stats.TopAuthors))
+	for i, a := range stats.TopAuthors {
+		topAuthors[i] = authorResponse{Author: a.Author, Books: a.Books, Pages: a.Pages}
+	}
+	
+	type decadeResponse struct {
+		Decade int `json:"decade"`
+		Count  int `json:"count"`
+	}
+	decades := make([]decadeResponse, len(stats.Decades))
+	for i, d := range stats.Decades {
+		decades[i] = decadeResponse{Decade: d.Decade, Count: d.Count}
+	}
+	
+	newAuthors, returningAuthors := books.CalculateAuthorNovelty(allBooks, year)
 	
 	response := map[string]interface{}{
 		"year":             stats.Year,
 		"totalBooks":       stats.TotalBooks,
 		"totalPages":       stats.TotalPages,
 		"averagePerMonth":  stats.AveragePerMonth,
+		"monthsElapsed":    stats.MonthsElapsed,
+		"medianPages":      stats.MedianPages,
+		"longestBook":      toBookLength(stats.Longest),
+		"shortestBook":     toBookLength(stats.Shortest),
+		"pageHistogram":    histogram,
+		"topAuthors":       topAuthors,
+		"newAuthors":       newAuthors,
+		"returningAuthors": returningAuthors,
+		"decades":          decades,
 		"monthlyBreakdown": breakdown,
+		"formats":          formatList,
+		"pageEquivalent":   pageEquivalent,
+		"timeToFinish": map[string]interface{}{
+			"count":             finish.Count,
+			"meanDays":          finish.MeanDays,
+			"medianDays":        finish.MedianDays,
+			"longest":           longest,
+			"maxConcurrent":     finish.MaxConcurrent,
+			"averageConcurrent": finish.AverageConcurrent,
+		},
+		"dnf": map[string]interface{}{
+			"count":     dnf.Count,
+			"rate":      dnf.Rate,
+			"pagesRead": dnf.PagesRead,
+			"reasons":   reasons,
+		},
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
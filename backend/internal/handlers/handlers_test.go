@@ -2,10 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/kristenwomack/reading-app/backend/internal/auth"
 	"github.com/kristenwomack/reading-app/backend/internal/books"
 )
 
@@ -85,6 +90,35 @@ func TestGetYears(t *testing.T) {
 	}
 }
 
+// TestGetYearsCountsFinishedReadsOfDNF verifies a year with only a read
+// we finished before abandoning the book is listed
+func TestGetYearsCountsFinishedReadsOfDNF(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Reread", Author: "A", Shelf: "Did Not Finish", DNFDate: "2025/04/10",
+			Reads: []books.Read{{DateFinished: "2020/06/01"}, {DateStarted: "2025/03/01"}}},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/years", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetYears(w, req)
+
+	var response struct {
+		Years []struct {
+			Year  int `json:"year"`
+			Count int `json:"count"`
+		} `json:"years"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Years) != 1 || response.Years[0].Year != 2020 || response.Years[0].Count != 1 {
+		t.Errorf("Expected one read in 2020, got %+v", response.Years)
+	}
+}
+
 // TestGetYearsEmpty verifies GetYears with no books
 func TestGetYearsEmpty(t *testing.T) {
 	// Setup with empty books
@@ -449,3 +483,965 @@ func TestGetBooksUsesStoredCoverURL(t *testing.T) {
 		t.Errorf("Expected generated cover URL %s, got %v", expectedURL, isbnCoverVal)
 	}
 }
+
+// TestGetSeries verifies the series listing groups read and unread entries
+func TestGetSeries(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Dawn (Xenogenesis, #1)", Author: "Octavia E. Butler", Shelf: "read", DateRead: "2024/03/01"},
+		{Title: "Adulthood Rites (Xenogenesis, #2)", Author: "Octavia E. Butler", Shelf: "to-read"},
+		{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"},
+	})
+	defer SetBooks(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/series", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetSeries(w, req)
+
+	// Verify response code
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response struct {
+		Series []struct {
+			Name   string                   `json:"name"`
+			Read   []struct{ Title string } `json:"read"`
+			Unread []struct{ Title string } `json:"unread"`
+			NextUp *struct {
+				Title    string  `json:"title"`
+				Position float64 `json:"position"`
+			} `json:"nextUp"`
+		} `json:"series"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(response.Series) != 1 {
+		t.Fatalf("Expected 1 series, got %d", len(response.Series))
+	}
+	s := response.Series[0]
+	if s.Name != "Xenogenesis" {
+		t.Errorf("Expected series Xenogenesis, got %q", s.Name)
+	}
+	if len(s.Read) != 1 || len(s.Unread) != 1 {
+		t.Errorf("Expected 1 read and 1 unread, got %d and %d", len(s.Read), len(s.Unread))
+	}
+	if s.NextUp == nil || s.NextUp.Position != 2 {
+		t.Errorf("Expected next up to be #2, got %+v", s.NextUp)
+	}
+}
+
+// TestGetStatsTimeToFinish verifies time-to-finish figures in the stats response
+func TestGetStatsTimeToFinish(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Short", Author: "A", DateStarted: "2025/01/01", DateRead: "2025/01/02", Shelf: "read"},
+		{Title: "Long", Author: "B", DateStarted: "2025/02/01", DateRead: "2025/02/20", Shelf: "read"},
+	})
+	defer SetBooks(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stats?year=2025", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetStats(w, req)
+
+	var response struct {
+		TimeToFinish struct {
+			Count      int     `json:"count"`
+			MedianDays float64 `json:"medianDays"`
+			Longest    []struct {
+				Title string `json:"title"`
+				Days  int    `json:"days"`
+			} `json:"longest"`
+		} `json:"timeToFinish"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.TimeToFinish.Count != 2 {
+		t.Errorf("Expected 2 timed reads, got %d", response.TimeToFinish.Count)
+	}
+	if response.TimeToFinish.MedianDays != 11 {
+		t.Errorf("Expected median 11 days, got %v", response.TimeToFinish.MedianDays)
+	}
+	if len(response.TimeToFinish.Longest) == 0 || response.TimeToFinish.Longest[0].Title != "Long" {
+		t.Errorf("Expected Long to be the longest read, got %+v", response.TimeToFinish.Longest)
+	}
+}
+
+// TestGetStatsPageEquivalent verifies audiobooks count toward pages on request
+func TestGetStatsPageEquivalent(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Print", Author: "A", DateRead: "2025/01/02", Pages: 300, Shelf: "read", Format: "print"},
+		{Title: "Audio", Author: "B", DateRead: "2025/02/20", Shelf: "read", Format: "audiobook", DurationMinutes: 600},
+	})
+	defer SetBooks(nil)
+
+	type statsResponse struct {
+		TotalPages int `json:"totalPages"`
+		Formats    []struct {
+			Format  string `json:"format"`
+			Books   int    `json:"books"`
+			Minutes int    `json:"minutes"`
+		} `json:"formats"`
+	}
+
+	// Without conversion only printed pages count
+	req := httptest.NewRequest(http.MethodGet, "/api/stats?year=2025", nil)
+	w := httptest.NewRecorder()
+	GetStats(w, req)
+
+	var plain statsResponse
+	if err := json.NewDecoder(w.Body).Decode(&plain); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if plain.TotalPages != 300 {
+		t.Errorf("Expected 300 pages, got %d", plain.TotalPages)
+	}
+	if plain.Formats[2].Format != "audiobook" || plain.Formats[2].Minutes != 600 {
+		t.Errorf("Expected 600 audiobook minutes, got %+v", plain.Formats[2])
+	}
+
+	// With conversion at 3 minutes per page the audiobook adds 200 pages
+	req = httptest.NewRequest(http.MethodGet, "/api/stats?year=2025&pageEquivalent=true&minutesPerPage=3", nil)
+	w = httptest.NewRecorder()
+	GetStats(w, req)
+
+	var converted statsResponse
+	if err := json.NewDecoder(w.Body).Decode(&converted); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if converted.TotalPages != 500 {
+		t.Errorf("Expected 500 pages, got %d", converted.TotalPages)
+	}
+}
+
+// TestGetStatsDNF verifies abandoned books are reported but not counted as read
+func TestGetStatsDNF(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Finished", Author: "A", DateRead: "2025/01/02", Shelf: "read"},
+		{Title: "Abandoned", Author: "B", Shelf: "dnf", DNFDate: "2025/02/01", DNFPage: 50, DNFReason: "Too slow"},
+	})
+	defer SetBooks(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stats?year=2025", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetStats(w, req)
+
+	var response struct {
+		TotalBooks int `json:"totalBooks"`
+		DNF        struct {
+			Count     int     `json:"count"`
+			Rate      float64 `json:"rate"`
+			PagesRead int     `json:"pagesRead"`
+		} `json:"dnf"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.TotalBooks != 1 {
+		t.Errorf("Expected 1 book read, got %d", response.TotalBooks)
+	}
+	if response.DNF.Count != 1 || response.DNF.Rate != 0.5 || response.DNF.PagesRead != 50 {
+		t.Errorf("Unexpected dnf stats: %+v", response.DNF)
+	}
+}
+
+// TestGetBooksOwnershipFilter verifies owned books can be listed without a year
+func TestGetBooksOwnershipFilter(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Owned", Author: "A", DateRead: "2024/01/02", Shelf: "read", Ownership: "owned", ShelfLocation: "Hall"},
+		{Title: "Wanted", Author: "B", Shelf: "to-read", Ownership: "wishlist"},
+		{Title: "Library", Author: "C", DateRead: "2025/03/02", Shelf: "read", Ownership: "library"},
+	})
+	defer SetBooks(nil)
+
+	token, err := auth.GenerateToken()
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/books?ownership=owned", nil)
+	req.AddCookie(&http.Cookie{Name: "auth_token", Value: token})
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetBooks(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var response struct {
+		Books []struct {
+			Title     string `json:"title"`
+			Ownership string `json:"ownership"`
+			Location  string `json:"shelfLocation"`
+		} `json:"books"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Books) != 1 || response.Books[0].Title != "Owned" || response.Books[0].Location != "Hall" {
+		t.Errorf("Expected only the owned book, got %+v", response.Books)
+	}
+}
+
+// TestGetBooksPrivateFields verifies purchases and shelf locations are
+// hidden from visitors and unknown owned formats are rejected
+func TestGetBooksPrivateFields(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Owned", Author: "A", DateRead: "2024/01/02", Shelf: "read", Ownership: "owned",
+			OwnedFormat: "print", PurchaseDate: "2023/12/01", PurchasePrice: 12.5, ShelfLocation: "Hall"},
+	})
+	defer SetBooks(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/books?year=2024", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetBooks(w, req)
+
+	body := w.Body.String()
+	for _, field := range []string{"purchaseDate", "purchasePrice", "shelfLocation"} {
+		if strings.Contains(body, field) {
+			t.Errorf("Expected %s hidden from visitors, got %s", field, body)
+		}
+	}
+
+	for path, want := range map[string]int{
+		"/api/books?ownedFormat=vinyl": http.StatusBadRequest,
+		"/api/books?ownedFormat=ebook": http.StatusOK,
+		"/api/books?location=Hall":     http.StatusUnauthorized,
+	} {
+		w := httptest.NewRecorder()
+		GetBooks(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", path, want, w.Code)
+		}
+	}
+}
+
+// TestGetLibraryValue verifies the library value summary
+func TestGetLibraryValue(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "One", Author: "A", Ownership: "owned", OwnedFormat: "print", PurchasePrice: 12.0},
+		{Title: "Two", Author: "B", Ownership: "owned", OwnedFormat: "print", PurchasePrice: 8.0},
+		{Title: "Three", Author: "C", Ownership: "borrowed"},
+	})
+	defer SetBooks(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/library/value", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetLibraryValue(w, req)
+
+	var response struct {
+		Owned      int     `json:"owned"`
+		TotalValue float64 `json:"totalValue"`
+		ByFormat   []struct {
+			Name  string  `json:"name"`
+			Value float64 `json:"value"`
+		} `json:"byFormat"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Owned != 2 || response.TotalValue != 20 {
+		t.Errorf("Expected 2 owned worth 20, got %d worth %v", response.Owned, response.TotalValue)
+	}
+	if len(response.ByFormat) != 1 || response.ByFormat[0].Name != "print" {
+		t.Errorf("Unexpected format groups: %+v", response.ByFormat)
+	}
+}
+
+// TestGetTBRPick verifies picks come from to-read and repeat with a seed
+func TestGetTBRPick(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Read One", Author: "A", DateRead: "2024/01/02", Shelf: "read"},
+		{Title: "Waiting", Author: "B", Shelf: "to-read", DateAdded: "2015/01/01"},
+		{Title: "Also Waiting", Author: "C", Shelf: "to-read", DateAdded: "2020/01/01"},
+	})
+	defer SetBooks(nil)
+
+	pick := func() []string {
+		req := httptest.NewRequest(http.MethodGet, "/api/tbr/pick?count=2&seed=7", nil)
+		w := httptest.NewRecorder()
+		GetTBRPick(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		var response struct {
+			Seed  string `json:"seed"`
+			Picks []struct {
+				Title   string   `json:"title"`
+				Reasons []string `json:"reasons"`
+			} `json:"picks"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Seed != "7" {
+			t.Errorf("Expected seed 7 echoed, got %q", response.Seed)
+		}
+		var titles []string
+		for _, p := range response.Picks {
+			if len(p.Reasons) == 0 {
+				t.Errorf("Expected reasons for %q", p.Title)
+			}
+			titles = append(titles, p.Title)
+		}
+		return titles
+	}
+
+	first := pick()
+	if len(first) != 2 || first[0] == "Read One" || first[1] == "Read One" {
+		t.Fatalf("Expected the two to-read books, got %v", first)
+	}
+	if second := pick(); second[0] != first[0] || second[1] != first[1] {
+		t.Errorf("Expected the same picks for the same seed, got %v and %v", first, second)
+	}
+}
+
+// TestGetTBRPickInvalidWeight verifies negative weights are rejected
+func TestGetTBRPickInvalidWeight(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/tbr/pick?weightAge=-1", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetTBRPick(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// TestGetTBRStats verifies the backlog report shape
+func TestGetTBRStats(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Waiting", Author: "A", Shelf: "to-read", DateAdded: "2015/01/01"},
+		{Title: "Stalled", Author: "B", Shelf: "to-read", DateAdded: "2016/01/01"},
+	})
+	defer SetBooks(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stats/tbr", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetTBRStats(w, req)
+
+	var response struct {
+		Size         int      `json:"size"`
+		YearsToClear *float64 `json:"yearsToClear"`
+		Oldest       []struct {
+			Title string `json:"title"`
+		} `json:"oldest"`
+		History []struct {
+			Size int `json:"size"`
+		} `json:"history"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Size != 2 || len(response.Oldest) != 2 || response.Oldest[0].Title != "Waiting" {
+		t.Errorf("Unexpected report: %+v", response)
+	}
+	if response.YearsToClear != nil {
+		t.Errorf("Expected no projection without completions, got %v", *response.YearsToClear)
+	}
+	if len(response.History) == 0 || response.History[len(response.History)-1].Size != 2 {
+		t.Errorf("Expected history ending at 2 books")
+	}
+}
+
+// TestGetStatsBreakdowns verifies the length, author and decade breakdowns
+func TestGetStatsBreakdowns(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Earlier", Author: "Repeat", Shelf: "read", DateRead: "2024/01/01", Pages: float64(100)},
+		{Title: "Short", Author: "Repeat", Shelf: "read", DateRead: "2025/01/10", Pages: float64(150), OriginalPublicationYear: float64(1985)},
+		{Title: "Long", Author: "Newcomer", Shelf: "read", DateRead: "2025/02/10", Pages: float64(650)},
+	})
+	defer SetBooks(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stats?year=2025", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetStats(w, req)
+
+	var response struct {
+		MedianPages float64 `json:"medianPages"`
+		LongestBook struct {
+			Title string `json:"title"`
+			Pages int    `json:"pages"`
+		} `json:"longestBook"`
+		ShortestBook struct {
+			Title string `json:"title"`
+		} `json:"shortestBook"`
+		PageHistogram []struct {
+			Count int `json:"count"`
+		} `json:"pageHistogram"`
+		TopAuthors []struct {
+			Author string `json:"author"`
+		} `json:"topAuthors"`
+		NewAuthors       int `json:"newAuthors"`
+		ReturningAuthors int `json:"returningAuthors"`
+		Decades          []struct {
+			Decade int `json:"decade"`
+			Count  int `json:"count"`
+		} `json:"decades"`
+		MonthsElapsed int `json:"monthsElapsed"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.MedianPages != 400 {
+		t.Errorf("Expected median 400, got %v", response.MedianPages)
+	}
+	if response.LongestBook.Title != "Long" || response.LongestBook.Pages != 650 || response.ShortestBook.Title != "Short" {
+		t.Errorf("Unexpected extremes: %+v / %+v", response.LongestBook, response.ShortestBook)
+	}
+	if len(response.PageHistogram) != 5 || response.PageHistogram[0].Count != 1 || response.PageHistogram[4].Count != 1 {
+		t.Errorf("Unexpected histogram: %+v", response.PageHistogram)
+	}
+	if len(response.TopAuthors) != 2 {
+		t.Errorf("Expected 2 top authors, got %+v", response.TopAuthors)
+	}
+	if response.NewAuthors != 1 || response.ReturningAuthors != 1 {
+		t.Errorf("Expected 1 new and 1 returning author, got %d and %d", response.NewAuthors, response.ReturningAuthors)
+	}
+	if len(response.Decades) != 1 || response.Decades[0].Decade != 1980 {
+		t.Errorf("Unexpected decades: %+v", response.Decades)
+	}
+	if response.MonthsElapsed != 12 {
+		t.Errorf("Expected 12 months elapsed for a past year, got %d", response.MonthsElapsed)
+	}
+}
+
+// TestGetYearReview verifies the structured year in review
+func TestGetYearReview(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "First", Author: "A", Shelf: "read", DateRead: "2025/01/05", Pages: float64(200), Rating: float64(5)},
+		{Title: "Last", Author: "B", Shelf: "read", DateRead: "2025/12/20", Pages: float64(400), Rating: float64(3)},
+	})
+	defer SetBooks(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/review/2025", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetYearReview(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var response struct {
+		Books     int `json:"books"`
+		FirstBook struct {
+			Title string `json:"title"`
+		} `json:"firstBook"`
+		LastBook struct {
+			Title string `json:"title"`
+		} `json:"lastBook"`
+		TopRated []struct {
+			Title  string `json:"title"`
+			Rating int    `json:"rating"`
+		} `json:"topRated"`
+		Months []struct {
+			Books int `json:"books"`
+		} `json:"months"`
+		Goal *struct{} `json:"goal"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Books != 2 || response.FirstBook.Title != "First" || response.LastBook.Title != "Last" {
+		t.Errorf("Unexpected review: %+v", response)
+	}
+	if len(response.TopRated) != 2 || response.TopRated[0].Rating != 5 {
+		t.Errorf("Unexpected top rated: %+v", response.TopRated)
+	}
+	if len(response.Months) != 12 {
+		t.Errorf("Expected 12 months, got %d", len(response.Months))
+	}
+	if response.Goal != nil {
+		t.Errorf("Expected no goal without a store")
+	}
+}
+
+// TestGetYearReviewHTML verifies the printable page is rendered and escaped
+func TestGetYearReviewHTML(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "<Tags> & Things", Author: "A", Shelf: "read", DateRead: "2025/03/05", Pages: float64(200)},
+	})
+	defer SetBooks(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/review/2025?format=html", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetYearReview(w, req)
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Expected HTML, got %s", ct)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "2025 in Review") || !strings.Contains(body, "Busiest month: March") {
+		t.Errorf("Missing review content: %s", body)
+	}
+	if !strings.Contains(body, "&lt;Tags&gt; &amp; Things") {
+		t.Errorf("Expected the title to be escaped")
+	}
+}
+
+// TestGetYearReviewInvalid verifies bad years and formats are rejected
+func TestGetYearReviewInvalid(t *testing.T) {
+	for _, path := range []string{"/api/review/abc", "/api/review/2025?format=pdf"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+
+		GetYearReview(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", path, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+// TestGetBadge verifies the SVG badge and its caching headers
+func TestGetBadge(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "One", Author: "A", Shelf: "read", DateRead: "2025/01/05", Pages: float64(200)},
+		{Title: "Two", Author: "B", Shelf: "read", DateRead: "2025/02/05", Pages: float64(300)},
+		{Title: "Later", Author: "C", Shelf: "to-read"},
+	})
+	defer SetBooks(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/badge/2025.svg?theme=dark&size=large&pages=true", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetBadge(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "image/svg+xml") {
+		t.Errorf("Expected SVG, got %s", ct)
+	}
+	if cc := w.Header().Get("Cache-Control"); !strings.Contains(cc, "max-age=") {
+		t.Errorf("Expected a max-age, got %q", cc)
+	}
+	body := w.Body.String()
+	for _, want := range []string{"<svg", `width="480"`, "2 books · 500 pages", "#0d1117"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in badge: %s", want, body)
+		}
+	}
+
+	// A matching ETag is not modified
+	etag := w.Header().Get("ETag")
+	req = httptest.NewRequest(http.MethodGet, "/api/badge/2025.svg?theme=dark&size=large&pages=true", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	GetBadge(w, req)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("Expected 304 with no body, got %d", w.Code)
+	}
+}
+
+// TestGetBadgeInvalid verifies bad paths and parameters are rejected
+func TestGetBadgeInvalid(t *testing.T) {
+	tests := map[string]int{
+		"/api/badge/2025":                  http.StatusNotFound,
+		"/api/badge/abc.svg":               http.StatusBadRequest,
+		"/api/badge/2025.svg?theme=purple": http.StatusBadRequest,
+		"/api/badge/2025.svg?size=huge":    http.StatusBadRequest,
+	}
+	for path, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+
+		GetBadge(w, req)
+
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", path, want, w.Code)
+		}
+	}
+}
+
+// TestGetFeedAtom verifies the Atom feed lists finished books newest first
+func TestGetFeedAtom(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Older", Author: "A", Shelf: "read", DateRead: "2025/01/05", ISBN13: "9780000000001"},
+		{Title: "Newer", Author: "B", Shelf: "read", Bookshelves: "read, fantasy", DateRead: "2025/02/05", MyReview: "Loved it.<br/>Would read again &amp; again."},
+		{Title: "Someday", Author: "C", Shelf: "to-read"},
+	})
+	defer SetBooks(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/feed.atom", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetFeed(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/atom+xml") {
+		t.Errorf("Expected Atom, got %s", ct)
+	}
+	var feed struct {
+		Updated string `xml:"updated"`
+		Entries []struct {
+			Title   string `xml:"title"`
+			ID      string `xml:"id"`
+			Summary string `xml:"summary"`
+			Content string `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.NewDecoder(w.Body).Decode(&feed); err != nil {
+		t.Fatalf("Failed to decode feed: %v", err)
+	}
+	if len(feed.Entries) != 2 || feed.Entries[0].Title != "Newer" {
+		t.Fatalf("Expected Newer then Older, got %+v", feed.Entries)
+	}
+	if feed.Updated != "2025-02-05T00:00:00Z" {
+		t.Errorf("Expected updated at the newest finish, got %s", feed.Updated)
+	}
+	if feed.Entries[0].Summary != "Loved it. Would read again & again." {
+		t.Errorf("Unexpected excerpt: %q", feed.Entries[0].Summary)
+	}
+	if !strings.HasPrefix(feed.Entries[1].ID, "tag:example.com,2025-01-05:") {
+		t.Errorf("Unexpected entry ID: %s", feed.Entries[1].ID)
+	}
+	if !strings.Contains(feed.Entries[1].Content, "covers.openlibrary.org/b/isbn/9780000000001") {
+		t.Errorf("Expected a cover in the content: %s", feed.Entries[1].Content)
+	}
+}
+
+// TestGetFeedRSS verifies the RSS feed, shelf filter and conditional GET
+func TestGetFeedRSS(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Plain", Author: "A", Shelf: "read", DateRead: "2025/01/05"},
+		{Title: "Dragons", Author: "B", Shelf: "read", Bookshelves: "read, fantasy", DateRead: "2025/02/05"},
+	})
+	defer SetBooks(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/feed.rss?shelf=fantasy", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetFeed(w, req)
+
+	var feed struct {
+		Channel struct {
+			Items []struct {
+				Title   string `xml:"title"`
+				PubDate string `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.NewDecoder(w.Body).Decode(&feed); err != nil {
+		t.Fatalf("Failed to decode feed: %v", err)
+	}
+	if len(feed.Channel.Items) != 1 || feed.Channel.Items[0].Title != "Dragons by B" {
+		t.Errorf("Expected only the fantasy book, got %+v", feed.Channel.Items)
+	}
+	if feed.Channel.Items[0].PubDate != "Wed, 05 Feb 2025 00:00:00 +0000" {
+		t.Errorf("Unexpected pubDate: %s", feed.Channel.Items[0].PubDate)
+	}
+
+	// A matching ETag is not modified; a date alone can't tell, since
+	// edits and deletions don't move the newest finish
+	if lm := w.Header().Get("Last-Modified"); lm != "" {
+		t.Errorf("Expected no Last-Modified, got %s", lm)
+	}
+	for header, want := range map[string]int{
+		"If-None-Match":     http.StatusNotModified,
+		"If-Modified-Since": http.StatusOK,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/feed.rss?shelf=fantasy", nil)
+		if header == "If-None-Match" {
+			req.Header.Set(header, w.Header().Get("ETag"))
+		} else {
+			req.Header.Set(header, time.Now().UTC().Format(http.TimeFormat))
+		}
+		w := httptest.NewRecorder()
+		GetFeed(w, req)
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", header, want, w.Code)
+		}
+	}
+}
+
+// TestGetCalendar verifies the public calendar lists full-date finishes
+func TestGetCalendar(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Dune, Part One; Again", Author: "Frank Herbert", Shelf: "read", DateRead: "2025/02/05", Pages: float64(600)},
+		{Title: "Month Only", Author: "A", Shelf: "read", DateRead: "2025/03"},
+	})
+	defer SetBooks(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/calendar.ics", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetCalendar(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("Expected text/calendar, got %s", ct)
+	}
+	body := w.Body.String()
+	if !strings.HasPrefix(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(body, "END:VCALENDAR\r\n") {
+		t.Errorf("Expected a CRLF-delimited calendar: %q", body)
+	}
+	if strings.Count(body, "BEGIN:VEVENT") != 1 {
+		t.Errorf("Expected only the full-date finish, got %d events", strings.Count(body, "BEGIN:VEVENT"))
+	}
+	for _, want := range []string{"DTSTART;VALUE=DATE:20250205", "DTEND;VALUE=DATE:20250206", `SUMMARY:Finished Dune\, Part One\; Again by Frank Herbert`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in calendar: %s", want, body)
+		}
+	}
+}
+
+// TestWriteICSLine verifies long lines are folded at 75 octets
+func TestWriteICSLine(t *testing.T) {
+	var b strings.Builder
+	writeICSLine(&b, "SUMMARY:"+strings.Repeat("é", 100))
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	if len(lines) < 3 {
+		t.Fatalf("Expected the line to be folded, got %d lines", len(lines))
+	}
+	for i, line := range lines {
+		if len(line) > 75 {
+			t.Errorf("Line %d is %d octets", i, len(line))
+		}
+		if i > 0 && !strings.HasPrefix(line, " ") {
+			t.Errorf("Continuation line %d should start with a space", i)
+		}
+	}
+	if unfolded := strings.ReplaceAll(b.String(), "\r\n ", ""); unfolded != "SUMMARY:"+strings.Repeat("é", 100)+"\r\n" {
+		t.Errorf("Unfolding should restore the line, got %q", unfolded)
+	}
+}
+
+// opdsTestFeed decodes the parts of an OPDS feed the tests check
+type opdsTestFeed struct {
+	ID           string `xml:"id"`
+	Title        string `xml:"title"`
+	TotalResults int    `xml:"totalResults"`
+	Links        []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"link"`
+	Entries []struct {
+		Title   string `xml:"title"`
+		Authors []struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+		Issued      string   `xml:"http://purl.org/dc/terms/ issued"`
+		Identifiers []string `xml:"http://purl.org/dc/terms/ identifier"`
+		Categories  []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
+// getOPDSFeed requests an OPDS path and decodes the feed
+func getOPDSFeed(t *testing.T, path string) (opdsTestFeed, *httptest.ResponseRecorder) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil)
+	w := httptest.NewRecorder()
+	GetOPDS(w, req)
+
+	var feed opdsTestFeed
+	if w.Code == http.StatusOK {
+		if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+			t.Fatalf("%s: failed to decode feed: %v", path, err)
+		}
+	}
+	return feed, w
+}
+
+// TestGetOPDSNavigation verifies the root and shelf navigation feeds
+func TestGetOPDSNavigation(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read", Bookshelves: "read, sci-fi", DateRead: "2024/02/01"},
+		{Title: "Dawn", Author: "Octavia Butler", Shelf: "to-read"},
+	})
+	defer SetBooks(nil)
+
+	// Execute
+	root, w := getOPDSFeed(t, "/opds")
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/atom+xml;profile=opds-catalog;kind=navigation" {
+		t.Errorf("Expected a navigation feed, got %s", ct)
+	}
+	if len(root.Entries) != 3 || root.Entries[0].Links[0].Href != "http://example.com/opds/shelves" {
+		t.Errorf("Unexpected root entries: %+v", root.Entries)
+	}
+	hasSearch := false
+	for _, l := range root.Links {
+		hasSearch = hasSearch || (l.Rel == "search" && l.Href == "http://example.com/opds/opensearch.xml")
+	}
+	if !hasSearch {
+		t.Errorf("Expected a search link, got %+v", root.Links)
+	}
+
+	shelves, _ := getOPDSFeed(t, "/opds/shelves")
+	var titles []string
+	for _, e := range shelves.Entries {
+		titles = append(titles, e.Title)
+	}
+	if strings.Join(titles, ",") != "read,sci-fi,to-read" {
+		t.Errorf("Unexpected shelves: %v", titles)
+	}
+
+	authors, _ := getOPDSFeed(t, "/opds/authors")
+	if len(authors.Entries) != 1 {
+		t.Errorf("Expected spellings of one author to merge, got %d", len(authors.Entries))
+	}
+}
+
+// TestGetOPDSBooks verifies book feeds carry metadata and covers but no acquisition links
+func TestGetOPDSBooks(t *testing.T) {
+	// Setup
+	SetBooks([]books.Book{
+		{Title: "Kindred", Author: "Octavia E. Butler", AdditionalAuthors: "Damian Duffy (Adapter), John Jennings", ISBN13: "9780807083697", OriginalPublicationYear: float64(1979), Shelf: "read", Bookshelves: "read, sci-fi", DateRead: "2024/02/01"},
+		{Title: "Dawn", Author: "Octavia Butler", Shelf: "to-read"},
+	})
+	defer SetBooks(nil)
+
+	// Execute
+	feed, w := getOPDSFeed(t, "/opds/shelves/sci-fi")
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/atom+xml;profile=opds-catalog;kind=acquisition" {
+		t.Errorf("Expected an acquisition feed, got %s", ct)
+	}
+	if len(feed.Entries) != 1 || feed.TotalResults != 1 {
+		t.Fatalf("Expected one sci-fi book, got %d", len(feed.Entries))
+	}
+	e := feed.Entries[0]
+	if len(e.Authors) != 2 || e.Authors[1].Name != "John Jennings" {
+		t.Errorf("Expected credited authors without the adapter, got %+v", e.Authors)
+	}
+	if e.Issued != "1979" || len(e.Identifiers) != 1 || e.Identifiers[0] != "urn:isbn:9780807083697" {
+		t.Errorf("Unexpected metadata: issued %q, identifiers %v", e.Issued, e.Identifiers)
+	}
+	if len(e.Categories) != 2 || e.Categories[0].Term != "read" {
+		t.Errorf("Unexpected categories: %+v", e.Categories)
+	}
+	for _, l := range e.Links {
+		if strings.HasPrefix(l.Rel, "http://opds-spec.org/acquisition") {
+			t.Errorf("Expected no acquisition links, got %+v", l)
+		}
+	}
+	if len(e.Links) != 2 || e.Links[0].Rel != "http://opds-spec.org/image" {
+		t.Errorf("Expected cover links, got %+v", e.Links)
+	}
+
+	byAuthor, _ := getOPDSFeed(t, "/opds/authors/octavia%20butler")
+	if len(byAuthor.Entries) != 2 {
+		t.Errorf("Expected both books by the author, got %d", len(byAuthor.Entries))
+	}
+	if byAuthor.ID != "urn:reading-tracker:opds:authors:octavia%20butler" || byAuthor.Links[0].Href != "http://example.com/opds/authors/octavia%20butler" {
+		t.Errorf("Expected the author escaped in the ID and self link, got %q and %+v", byAuthor.ID, byAuthor.Links[0])
+	}
+	if _, w := getOPDSFeed(t, "/opds/shelves/100%25"); w.Code != http.StatusOK {
+		t.Errorf("Expected a shelf named with %% to be found, got status %d", w.Code)
+	}
+	byYear, _ := getOPDSFeed(t, "/opds/years/2024")
+	if len(byYear.Entries) != 1 || byYear.Title != "Read in 2024" {
+		t.Errorf("Unexpected year feed: %+v", byYear)
+	}
+	search, _ := getOPDSFeed(t, "/opds/search?q=daw")
+	if len(search.Entries) != 1 || search.Entries[0].Title != "Dawn" {
+		t.Errorf("Unexpected search results: %+v", search.Entries)
+	}
+}
+
+// TestGetOPDSPaging verifies large feeds link to their next and previous pages
+func TestGetOPDSPaging(t *testing.T) {
+	// Setup
+	var list []books.Book
+	for i := 0; i < 120; i++ {
+		list = append(list, books.Book{Title: fmt.Sprintf("Book %d", i), Author: "A", Shelf: "to-read"})
+	}
+	SetBooks(list)
+	defer SetBooks(nil)
+
+	// Execute
+	feed, _ := getOPDSFeed(t, "/opds/shelves/to-read?page=2")
+
+	rels := map[string]string{}
+	for _, l := range feed.Links {
+		rels[l.Rel] = l.Href
+	}
+	if len(feed.Entries) != 50 || feed.Entries[0].Title != "Book 50" || feed.TotalResults != 120 {
+		t.Errorf("Unexpected page: %d entries of %d", len(feed.Entries), feed.TotalResults)
+	}
+	if rels["next"] != "http://example.com/opds/shelves/to-read?page=3" || rels["previous"] != "http://example.com/opds/shelves/to-read?page=1" {
+		t.Errorf("Unexpected paging links: %v", rels)
+	}
+
+	if _, w := getOPDSFeed(t, "/opds/shelves/to-read?page=0"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for page 0, got %d", http.StatusBadRequest, w.Code)
+	}
+	if _, w := getOPDSFeed(t, "/opds/unknown"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for an unknown feed, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// TestGetOPDSOpenSearch verifies the description points at both searches
+func TestGetOPDSOpenSearch(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/opds/opensearch.xml", nil)
+	w := httptest.NewRecorder()
+
+	// Execute
+	GetOPDS(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/opensearchdescription+xml" {
+		t.Errorf("Unexpected Content-Type: %s", ct)
+	}
+	body := w.Body.String()
+	for _, want := range []string{`template="http://example.com/opds/search?q={searchTerms}"`, `template="http://example.com/api/search?q={searchTerms}"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %s in description: %s", want, body)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/kristenwomack/reading-app/backend/internal/store"
+)
+
+// maxImportSize caps the size of an uploaded library export
+const maxImportSize = 50 << 20
+
+// ImportLibrary handles POST /api/import/{source}, adding books from
+// another app's export (see store.Importers). Like clippings, the file may
+// be the raw request body or the "file" field of a multipart form.
+func ImportLibrary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	importer, ok := store.Importers[strings.TrimPrefix(r.URL.Path, "/api/import/")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := uploadedFile(w, r, maxImportSize)
+	if err != nil {
+		http.Error(w, "Missing file", http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+
+	parsed, err := importer.Parse(body)
+	if err != nil {
+		http.Error(w, "Invalid "+importer.Source()+" export", http.StatusBadRequest)
+		return
+	}
+	result, err := dataStore.ImportParsed(parsed)
+	if err != nil {
+		http.Error(w, "Failed to import books", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"source":     importer.Source(),
+		"imported":   result.Imported,
+		"duplicates": result.Duplicates,
+		"skipped":    result.Skipped,
+	})
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// valueGroupResponse is the owned books and value for a format or location
+type valueGroupResponse struct {
+	Name  string  `json:"name"`
+	Count int     `json:"count"`
+	Value float64 `json:"value"`
+}
+
+// toValueGroupResponses converts books.ValueGroup values for the API
+func toValueGroupResponses(groups []books.ValueGroup) []valueGroupResponse {
+	result := make([]valueGroupResponse, len(groups))
+	for i, g := range groups {
+		result[i] = valueGroupResponse{Name: g.Name, Count: g.Count, Value: g.Value}
+	}
+	return result
+}
+
+// GetLibraryValue handles GET /api/library/value
+func GetLibraryValue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	value := books.CalculateLibraryValue(getBooks())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"owned":      value.Owned,
+		"priced":     value.Priced,
+		"totalValue": value.TotalValue,
+		"avgPrice":   value.AvgPrice,
+		"byFormat":   toValueGroupResponses(value.ByFormat),
+		"byLocation": toValueGroupResponses(value.ByLocation),
+		"ownership":  value.Ownership,
+	})
+}
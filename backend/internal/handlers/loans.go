@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+	"github.com/kristenwomack/reading-app/backend/internal/store"
+)
+
+// loanResponse is a lent book
+type loanResponse struct {
+	ID           int64  `json:"id"`
+	BookID       int64  `json:"bookId"`
+	Title        string `json:"title"`
+	Author       string `json:"author"`
+	Borrower     string `json:"borrower"`
+	LentDate     string `json:"lentDate"`
+	DueDate      string `json:"dueDate,omitempty"`
+	ReturnedDate string `json:"returnedDate,omitempty"`
+	Outstanding  bool   `json:"outstanding"`
+	Overdue      bool   `json:"overdue"`
+	DaysOverdue  int    `json:"daysOverdue,omitempty"`
+}
+
+// today returns the current date as YYYY/MM/DD
+func today() string {
+	return time.Now().Format("2006/01/02")
+}
+
+// parseLoanDate checks and zero-pads a full YYYY/MM/DD date so loan dates
+// compare as strings. An empty date becomes def.
+func parseLoanDate(date, def string) (string, bool) {
+	if date == "" {
+		return def, true
+	}
+	parsed, err := books.ParseDate(date)
+	if err != nil {
+		return "", false
+	}
+	t, ok := parsed.Time()
+	if !ok {
+		return "", false
+	}
+	return t.Format("2006/01/02"), true
+}
+
+// toLoanResponse converts a store.Loan, flagging it overdue as of now
+func toLoanResponse(l store.Loan, now string) loanResponse {
+	resp := loanResponse{
+		ID:           l.ID,
+		BookID:       l.BookID,
+		Title:        l.Title,
+		Author:       l.Author,
+		Borrower:     l.Borrower,
+		LentDate:     l.LentDate,
+		DueDate:      l.DueDate,
+		ReturnedDate: l.ReturnedDate,
+		Outstanding:  l.Outstanding(),
+		Overdue:      l.Overdue(now),
+	}
+	if resp.Overdue {
+		due, _ := time.Parse("2006/01/02", l.DueDate)
+		current, _ := time.Parse("2006/01/02", now)
+		resp.DaysOverdue = int(current.Sub(due).Hours() / 24)
+	}
+	return resp
+}
+
+// GetLoans handles GET /api/loans?status=outstanding|overdue|returned
+func GetLoans(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", "all", "outstanding", "overdue", "returned":
+	default:
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	loans, err := dataStore.GetLoans(status == "outstanding" || status == "overdue")
+	if err != nil {
+		http.Error(w, "Failed to get loans", http.StatusInternalServerError)
+		return
+	}
+
+	now := today()
+	result := []loanResponse{}
+	overdue := 0
+	for _, l := range loans {
+		resp := toLoanResponse(l, now)
+		if resp.Overdue {
+			overdue++
+		}
+		if (status == "overdue" && !resp.Overdue) || (status == "returned" && resp.Outstanding) {
+			continue
+		}
+		result = append(result, resp)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"loans":   result,
+		"overdue": overdue,
+	})
+}
+
+// LendBook handles POST /api/books/{id}/lend
+func LendBook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	bookID, ok := parseBookSubpath(r.URL.Path, "lend")
+	if !ok {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Borrower string `json:"borrower"`
+		LentDate string `json:"lentDate"`
+		DueDate  string `json:"dueDate"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	req.Borrower = strings.TrimSpace(req.Borrower)
+	if req.Borrower == "" {
+		http.Error(w, "Borrower is required", http.StatusBadRequest)
+		return
+	}
+	lentDate, ok := parseLoanDate(req.LentDate, today())
+	if !ok {
+		http.Error(w, "Invalid lent date", http.StatusBadRequest)
+		return
+	}
+	dueDate, ok := parseLoanDate(req.DueDate, "")
+	if !ok || (dueDate != "" && dueDate < lentDate) {
+		http.Error(w, "Invalid due date", http.StatusBadRequest)
+		return
+	}
+
+	book, err := dataStore.GetBook(bookID)
+	if err != nil {
+		http.Error(w, "Failed to get book", http.StatusInternalServerError)
+		return
+	}
+	if book == nil {
+		http.Error(w, "Book not found", http.StatusNotFound)
+		return
+	}
+
+	existing, err := dataStore.GetOutstandingLoan(bookID)
+	if err != nil {
+		http.Error(w, "Failed to get loans", http.StatusInternalServerError)
+		return
+	}
+	if existing != nil {
+		http.Error(w, "Book is already lent to "+existing.Borrower, http.StatusConflict)
+		return
+	}
+
+	id, err := dataStore.CreateLoan(&store.Loan{
+		BookID:   bookID,
+		Borrower: req.Borrower,
+		LentDate: lentDate,
+		DueDate:  dueDate,
+	})
+	if err != nil {
+		http.Error(w, "Failed to lend book", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]int64{"id": id})
+}
+
+// ReturnLoan handles POST /api/loans/{id}/return
+func ReturnLoan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path, found := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/loans/"), "/return")
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	id, err := strconv.ParseInt(path, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid loan ID", http.StatusBadRequest)
+		return
+	}
+
+	// The body is optional; without one the book comes back today
+	var req struct {
+		ReturnedDate string `json:"returnedDate"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request", http.StatusBadRequest)
+			return
+		}
+	}
+
+	loan, err := dataStore.GetLoan(id)
+	if err != nil {
+		http.Error(w, "Failed to get loan", http.StatusInternalServerError)
+		return
+	}
+	if loan == nil {
+		http.Error(w, "Loan not found", http.StatusNotFound)
+		return
+	}
+	if !loan.Outstanding() {
+		http.Error(w, "Loan already returned", http.StatusConflict)
+		return
+	}
+
+	returned, ok := parseLoanDate(req.ReturnedDate, today())
+	if !ok || returned < loan.LentDate {
+		http.Error(w, "Invalid returned date", http.StatusBadRequest)
+		return
+	}
+
+	if err := dataStore.ReturnLoan(id, returned); err != nil {
+		http.Error(w, "Failed to return loan", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"archive/zip"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+	"github.com/kristenwomack/reading-app/backend/internal/store"
+)
+
+// reviewMarkup converts the inline HTML Goodreads allows in reviews
+var reviewMarkup = strings.NewReplacer(
+	"<i>", "*", "</i>", "*", "<em>", "*", "</em>", "*",
+	"<b>", "**", "</b>", "**", "<strong>", "**", "</strong>", "**",
+)
+
+// lineBreak matches <br>, <br/> and <br />
+var lineBreak = regexp.MustCompile(`(?i)<br\s*/?>`)
+
+// unsafeFileChars are characters Obsidian or common file systems reject in
+// note names
+var unsafeFileChars = regexp.MustCompile(`[\\/:*?"<>|#^\[\]]+`)
+
+// yamlDate converts a YYYY/MM/DD date to the YYYY-MM-DD form Obsidian
+// recognises as a date property; partial dates stay quoted text
+func yamlDate(date string) string {
+	parsed, err := books.ParseDate(date)
+	if err != nil {
+		return ""
+	}
+	if t, ok := parsed.Time(); ok {
+		return t.Format("2006-01-02")
+	}
+	return strconv.Quote(strings.ReplaceAll(date, "/", "-"))
+}
+
+// reviewMarkdown converts a review's HTML to Markdown
+func reviewMarkdown(review string) string {
+	text := lineBreak.ReplaceAllString(review, "\n")
+	text = reviewMarkup.Replace(text)
+	text = html.UnescapeString(htmlTag.ReplaceAllString(text, ""))
+	return strings.TrimSpace(text)
+}
+
+// noteFileName returns the note name for a book: its title, made safe for
+// file names
+func noteFileName(b store.Book) string {
+	title, _ := books.ParseSeries(b.Title)
+	name := strings.TrimSpace(unsafeFileChars.ReplaceAllString(title, " "))
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		name = "Untitled"
+	}
+	return name
+}
+
+// finishDates returns the finish date of every finished read of a book
+// (see books.Book.FinishedReads)
+func finishDates(rec exportRecord) []string {
+	b := books.Book{
+		Shelf:       rec.book.Shelf,
+		DateStarted: rec.book.DateStarted,
+		DateRead:    rec.book.DateRead,
+		Reads:       convertStoreReads(rec.reads),
+	}
+	return b.ReadDates()
+}
+
+// bookNote renders a book as Markdown with YAML front matter
+func bookNote(rec exportRecord) string {
+	b := rec.book
+	title, _ := books.ParseSeries(b.Title)
+
+	var out strings.Builder
+	out.WriteString("---\n")
+	fmt.Fprintf(&out, "title: %s\n", strconv.Quote(title))
+	fmt.Fprintf(&out, "author: %s\n", strconv.Quote(b.Author))
+	if b.AdditionalAuthors != "" {
+		fmt.Fprintf(&out, "additional_authors: %s\n", strconv.Quote(b.AdditionalAuthors))
+	}
+	if b.ISBN != "" {
+		fmt.Fprintf(&out, "isbn: %s\n", strconv.Quote(b.ISBN))
+	}
+	if b.ISBN13 != "" {
+		fmt.Fprintf(&out, "isbn13: %s\n", strconv.Quote(b.ISBN13))
+	}
+	if b.Series != "" {
+		fmt.Fprintf(&out, "series: %s\n", strconv.Quote(b.Series))
+	}
+	for _, field := range [][2]string{
+		{"date_added", b.DateAdded},
+		{"date_started", b.DateStarted},
+		{"date_read", b.DateRead},
+	} {
+		if d := yamlDate(field[1]); d != "" {
+			fmt.Fprintf(&out, "%s: %s\n", field[0], d)
+		}
+	}
+	if dates := finishDates(rec); len(dates) > 1 {
+		out.WriteString("reads:\n")
+		for _, d := range dates {
+			if y := yamlDate(d); y != "" {
+				fmt.Fprintf(&out, "  - %s\n", y)
+			}
+		}
+	}
+	fmt.Fprintf(&out, "shelf: %s\n", strconv.Quote(b.Shelf))
+	if b.Rating > 0 {
+		fmt.Fprintf(&out, "rating: %g\n", b.Rating)
+	}
+	if b.Pages > 0 {
+		fmt.Fprintf(&out, "pages: %d\n", b.Pages)
+	}
+	if tags := books.ParseTags(b.Tags); len(tags) > 0 {
+		out.WriteString("tags:\n")
+		for _, t := range tags {
+			fmt.Fprintf(&out, "  - %s\n", strconv.Quote(t))
+		}
+	}
+	out.WriteString("---\n\n")
+
+	fmt.Fprintf(&out, "# %s\n\n*by %s*\n", title, b.Author)
+	if review := reviewMarkdown(b.Review); review != "" {
+		fmt.Fprintf(&out, "\n## Review\n\n%s\n", review)
+	}
+	if len(rec.notes) > 0 {
+		out.WriteString("\n## Notes\n")
+		for _, n := range rec.notes {
+			where := ""
+			if n.Page > 0 {
+				where = fmt.Sprintf(" (p. %d)", n.Page)
+			} else if n.Location != "" {
+				where = fmt.Sprintf(" (loc. %s)", n.Location)
+			}
+			text := strings.TrimSpace(n.Text)
+			if n.Kind == store.NoteKindNote {
+				fmt.Fprintf(&out, "\n%s%s\n", text, where)
+			} else {
+				fmt.Fprintf(&out, "\n> %s%s\n", strings.ReplaceAll(text, "\n", "\n> "), where)
+			}
+		}
+	}
+	return out.String()
+}
+
+// yearEntry is one finished read listed in a year's index
+type yearEntry struct {
+	rec  exportRecord
+	name string
+	date books.ParsedDate
+}
+
+// yearNote renders the index of books finished in a year, oldest first
+func yearNote(year int, entries []yearEntry) string {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].date, entries[j].date
+		if a.Month != b.Month {
+			return a.Month < b.Month
+		}
+		return a.Day < b.Day
+	})
+	pages := 0
+	for _, e := range entries {
+		pages += e.rec.book.Pages
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "---\nyear: %d\nbooks: %d\npages: %d\n---\n\n# %d\n\n", year, len(entries), pages, year)
+	for _, e := range entries {
+		fmt.Fprintf(&out, "- %s [[%s]] by %s", strings.ReplaceAll(e.date.String(), "/", "-"), e.name, e.rec.book.Author)
+		if r := e.rec.book.Rating; r > 0 {
+			out.WriteString(" " + strings.Repeat("★", books.WholeStars(r)))
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// writeMarkdownZip writes a zip of Books/<title>.md notes and a
+// Years/<year>.md index for every year with finished reads. Books sharing
+// a title are told apart by citation key.
+func writeMarkdownZip(w io.Writer, records []exportRecord) error {
+	names := make(map[string]int)
+	for _, rec := range records {
+		names[strings.ToLower(noteFileName(rec.book))]++
+	}
+
+	zw := zip.NewWriter(w)
+	byYear := make(map[int][]yearEntry)
+	for _, rec := range records {
+		name := noteFileName(rec.book)
+		if names[strings.ToLower(name)] > 1 {
+			name += " (" + rec.book.CitationKey + ")"
+		}
+		f, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     "Books/" + name + ".md",
+			Method:   zip.Deflate,
+			Modified: rec.book.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, bookNote(rec)); err != nil {
+			return err
+		}
+
+		for _, d := range finishDates(rec) {
+			if parsed, err := books.ParseDate(d); err == nil {
+				byYear[parsed.Year] = append(byYear[parsed.Year], yearEntry{rec: rec, name: name, date: parsed})
+			}
+		}
+	}
+
+	years := make([]int, 0, len(byYear))
+	for y := range byYear {
+		years = append(years, y)
+	}
+	sort.Ints(years)
+	for _, y := range years {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: fmt.Sprintf("Years/%d.md", y), Method: zip.Deflate})
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, yearNote(y, byYear[y])); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
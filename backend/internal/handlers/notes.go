@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kristenwomack/reading-app/backend/internal/store"
+)
+
+// noteResponse is a quote, highlight or note on a book
+type noteResponse struct {
+	ID        int64  `json:"id"`
+	BookID    int64  `json:"bookId"`
+	Kind      string `json:"kind"`
+	Text      string `json:"text"`
+	Page      int    `json:"page,omitempty"`
+	Location  string `json:"location,omitempty"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// NoteRequest represents a note creation/update request
+type NoteRequest struct {
+	Kind     string `json:"kind"`
+	Text     string `json:"text"`
+	Page     int    `json:"page"`
+	Location string `json:"location"`
+}
+
+// validate checks a note request and defaults its kind
+func (req *NoteRequest) validate() string {
+	if strings.TrimSpace(req.Text) == "" {
+		return "Text is required"
+	}
+	if req.Kind == "" {
+		req.Kind = store.NoteKindNote
+	}
+	switch req.Kind {
+	case store.NoteKindQuote, store.NoteKindHighlight, store.NoteKindNote:
+	default:
+		return "Kind must be quote, highlight or note"
+	}
+	if req.Page < 0 {
+		return "Page must not be negative"
+	}
+	return ""
+}
+
+// toNoteResponse converts a store.Note for the API
+func toNoteResponse(n store.Note) noteResponse {
+	return noteResponse{
+		ID:        n.ID,
+		BookID:    n.BookID,
+		Kind:      n.Kind,
+		Text:      n.Text,
+		Page:      n.Page,
+		Location:  n.Location,
+		CreatedAt: n.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: n.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// parseBookSubpath extracts the book ID from /api/books/{id}/{sub}
+func parseBookSubpath(path, sub string) (int64, bool) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/books/"), "/"), "/")
+	if len(parts) != 2 || parts[1] != sub {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	return id, err == nil
+}
+
+// GetNotes handles GET /api/books/{id}/notes
+func GetNotes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	bookID, ok := parseBookSubpath(r.URL.Path, "notes")
+	if !ok {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+
+	notes, err := dataStore.GetNotes(bookID)
+	if err != nil {
+		http.Error(w, "Failed to get notes", http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]noteResponse, len(notes))
+	for i, n := range notes {
+		result[i] = toNoteResponse(n)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"notes": result})
+}
+
+// CreateNote handles POST /api/books/{id}/notes
+func CreateNote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	bookID, ok := parseBookSubpath(r.URL.Path, "notes")
+	if !ok {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+
+	var req NoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	book, err := dataStore.GetBook(bookID)
+	if err != nil {
+		http.Error(w, "Failed to get book", http.StatusInternalServerError)
+		return
+	}
+	if book == nil {
+		http.Error(w, "Book not found", http.StatusNotFound)
+		return
+	}
+
+	id, err := dataStore.CreateNote(&store.Note{
+		BookID:   bookID,
+		Kind:     req.Kind,
+		Text:     req.Text,
+		Page:     req.Page,
+		Location: req.Location,
+	})
+	if err != nil {
+		http.Error(w, "Failed to create note", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]int64{"id": id})
+}
+
+// UpdateNote handles PUT /api/notes/{id}
+func UpdateNote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract ID from path
+	path := strings.TrimPrefix(r.URL.Path, "/api/notes/")
+	id, err := strconv.ParseInt(path, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid note ID", http.StatusBadRequest)
+		return
+	}
+
+	var req NoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	note, err := dataStore.GetNote(id)
+	if err != nil {
+		http.Error(w, "Failed to get note", http.StatusInternalServerError)
+		return
+	}
+	if note == nil {
+		http.Error(w, "Note not found", http.StatusNotFound)
+		return
+	}
+
+	note.Kind = req.Kind
+	note.Text = req.Text
+	note.Page = req.Page
+	note.Location = req.Location
+	if err := dataStore.UpdateNote(note); err != nil {
+		http.Error(w, "Failed to update note", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// DeleteNote handles DELETE /api/notes/{id}
+func DeleteNote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract ID from path
+	path := strings.TrimPrefix(r.URL.Path, "/api/notes/")
+	id, err := strconv.ParseInt(path, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid note ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := dataStore.DeleteNote(id); err != nil {
+		http.Error(w, "Failed to delete note", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
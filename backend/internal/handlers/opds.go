@@ -0,0 +1,476 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// OPDS 1.2 feed media types
+const (
+	opdsNavigation  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	opdsAcquisition = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	openSearchType  = "application/opensearchdescription+xml"
+)
+
+// opdsPageSize is how many books an acquisition feed lists per page
+const opdsPageSize = 50
+
+// opdsFeed is an OPDS catalog feed: navigation or acquisition
+type opdsFeed struct {
+	XMLName      xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID           string      `xml:"id"`
+	Title        string      `xml:"title"`
+	Updated      string      `xml:"updated"`
+	Links        []atomLink  `xml:"link"`
+	TotalResults int         `xml:"http://a9.com/-/spec/opensearch/1.1/ totalResults,omitempty"`
+	ItemsPerPage int         `xml:"http://a9.com/-/spec/opensearch/1.1/ itemsPerPage,omitempty"`
+	StartIndex   int         `xml:"http://a9.com/-/spec/opensearch/1.1/ startIndex,omitempty"`
+	Entries      []opdsEntry `xml:"entry"`
+}
+
+// opdsEntry is a navigation link or a book. Books carry metadata and
+// covers but no acquisition links: the catalog lists what we've read and
+// own, not files to download.
+type opdsEntry struct {
+	Title       string         `xml:"title"`
+	ID          string         `xml:"id"`
+	Updated     string         `xml:"updated"`
+	Authors     []atomPerson   `xml:"author"`
+	Issued      string         `xml:"http://purl.org/dc/terms/ issued,omitempty"`
+	Publisher   string         `xml:"http://purl.org/dc/terms/ publisher,omitempty"`
+	Identifiers []string       `xml:"http://purl.org/dc/terms/ identifier"`
+	Categories  []opdsCategory `xml:"category"`
+	Summary     string         `xml:"summary,omitempty"`
+	Content     *atomText      `xml:"content"`
+	Links       []atomLink     `xml:"link"`
+}
+
+// opdsCategory is a shelf or tag on a book
+type opdsCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr,omitempty"`
+}
+
+// openSearchDescription describes the catalog's search (OpenSearch 1.1)
+type openSearchDescription struct {
+	XMLName     xml.Name        `xml:"http://a9.com/-/spec/opensearch/1.1/ OpenSearchDescription"`
+	ShortName   string          `xml:"ShortName"`
+	Description string          `xml:"Description"`
+	InputEnc    string          `xml:"InputEncoding"`
+	URLs        []openSearchURL `xml:"Url"`
+}
+
+// openSearchURL is a search URL template
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Template string `xml:"template,attr"`
+}
+
+// opdsNav is a navigation entry leading to another feed
+type opdsNav struct {
+	title      string
+	path       string
+	count      int
+	navigation bool // leads to another navigation feed rather than books
+}
+
+// writeOPDS encodes an OPDS document with its media type
+func writeOPDS(w http.ResponseWriter, contentType string, doc interface{}) {
+	var body bytes.Buffer
+	body.WriteString(xml.Header)
+	enc := xml.NewEncoder(&body)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		http.Error(w, "Failed to render catalog", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(body.Bytes())
+}
+
+// opdsLinks returns the links every catalog feed carries
+func opdsLinks(base, self, selfType string) []atomLink {
+	return []atomLink{
+		{Href: base + self, Rel: "self", Type: selfType},
+		{Href: base + "/opds", Rel: "start", Type: opdsNavigation},
+		{Href: base + "/opds/opensearch.xml", Rel: "search", Type: openSearchType},
+	}
+}
+
+// navigationFeed builds a feed of links to other feeds
+func navigationFeed(base, path, title string, navs []opdsNav, now string) opdsFeed {
+	feed := opdsFeed{
+		ID:      "urn:reading-tracker:opds" + strings.ReplaceAll(path, "/", ":"),
+		Title:   title,
+		Updated: now,
+		Links:   opdsLinks(base, "/opds"+path, opdsNavigation),
+	}
+	if path != "" {
+		feed.Links = append(feed.Links, atomLink{Href: base + "/opds", Rel: "up", Type: opdsNavigation})
+	}
+	for _, n := range navs {
+		linkType := opdsAcquisition
+		if n.navigation {
+			linkType = opdsNavigation
+		}
+		entry := opdsEntry{
+			Title:   n.title,
+			ID:      "urn:reading-tracker:opds" + strings.ReplaceAll(n.path, "/", ":"),
+			Updated: now,
+			Links:   []atomLink{{Href: base + "/opds" + n.path, Rel: "subsection", Type: linkType}},
+		}
+		if n.count > 0 {
+			entry.Content = &atomText{Type: "text", Body: fmt.Sprintf("%d books", n.count)}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return feed
+}
+
+// opdsBookEntry describes a book with its metadata and cover
+func opdsBookEntry(b books.Book, now string) opdsEntry {
+	entry := opdsEntry{
+		Title:   b.GetTitle(),
+		ID:      "urn:reading-tracker:book:" + bookKey(b),
+		Updated: now,
+	}
+	for _, date := range []string{b.DateRead, b.GetDateAdded()} {
+		if d, err := books.ParseDate(date); err == nil {
+			if t, ok := d.Time(); ok {
+				entry.Updated = t.Format(time.RFC3339)
+				break
+			}
+		}
+	}
+
+	additional, _ := b.AdditionalAuthors.(string)
+	for _, c := range books.ParseAuthorCredits(b.Author, additional) {
+		if c.Role == "author" {
+			entry.Authors = append(entry.Authors, atomPerson{Name: c.Name})
+		}
+	}
+	if year := b.GetPublicationYear(); year > 0 {
+		entry.Issued = strconv.Itoa(year)
+	}
+	entry.Publisher, _ = b.Publisher.(string)
+	if isbn := getISBN(b); isbn != "" {
+		entry.Identifiers = append(entry.Identifiers, "urn:isbn:"+isbn)
+	}
+
+	shelves := b.GetTags()
+	if shelf := books.NormalizeShelf(b.Shelf); shelf != "" {
+		shelves = append([]string{shelf}, shelves...)
+	}
+	for _, shelf := range shelves {
+		entry.Categories = append(entry.Categories, opdsCategory{Term: shelf, Label: shelf})
+	}
+
+	if review, ok := b.MyReview.(string); ok {
+		entry.Summary = excerpt(review, excerptLength)
+	}
+	var details []string
+	if pages := b.GetPages(); pages > 0 {
+		details = append(details, fmt.Sprintf("%d pages", pages))
+	}
+	if b.Series != "" {
+		details = append(details, "Series: "+b.Series)
+	}
+	if b.DateRead != "" {
+		details = append(details, "Finished "+b.DateRead)
+	}
+	if rating := b.GetRating(); rating > 0 {
+		details = append(details, fmt.Sprintf("Rated %g/5", rating))
+	}
+	if len(details) > 0 {
+		entry.Content = &atomText{Type: "text", Body: strings.Join(details, " · ")}
+	}
+
+	if cover := getCoverURL(b); cover != "" {
+		entry.Links = append(entry.Links,
+			atomLink{Href: cover, Rel: "http://opds-spec.org/image", Type: "image/jpeg"},
+			atomLink{Href: cover, Rel: "http://opds-spec.org/image/thumbnail", Type: "image/jpeg"})
+	}
+	return entry
+}
+
+// bookFeed builds an acquisition feed of one page of books. path is the
+// escaped request path.
+func bookFeed(r *http.Request, base, path, title string, list []books.Book, now string) (opdsFeed, bool) {
+	page := 1
+	if p := r.URL.Query().Get("page"); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 1 {
+			return opdsFeed{}, false
+		}
+		page = n
+	}
+
+	self := path
+	if q := r.URL.Query(); len(q) > 0 {
+		self += "?" + q.Encode()
+	}
+	feed := opdsFeed{
+		ID:           "urn:reading-tracker:opds" + strings.ReplaceAll(strings.TrimPrefix(path, "/opds"), "/", ":"),
+		Title:        title,
+		Updated:      now,
+		Links:        append(opdsLinks(base, self, opdsAcquisition), atomLink{Href: base + "/opds", Rel: "up", Type: opdsNavigation}),
+		TotalResults: len(list),
+		ItemsPerPage: opdsPageSize,
+		StartIndex:   (page-1)*opdsPageSize + 1,
+	}
+
+	pageURL := func(n int) string {
+		q := r.URL.Query()
+		q.Set("page", strconv.Itoa(n))
+		return base + path + "?" + q.Encode()
+	}
+	if page > 1 {
+		feed.Links = append(feed.Links, atomLink{Href: pageURL(page - 1), Rel: "previous", Type: opdsAcquisition})
+	}
+	if page*opdsPageSize < len(list) {
+		feed.Links = append(feed.Links, atomLink{Href: pageURL(page + 1), Rel: "next", Type: opdsAcquisition})
+	}
+
+	start := min((page-1)*opdsPageSize, len(list))
+	end := min(start+opdsPageSize, len(list))
+	for _, b := range list[start:end] {
+		feed.Entries = append(feed.Entries, opdsBookEntry(b, now))
+	}
+	return feed, true
+}
+
+// shelfNavs lists every shelf and tag with its book count
+func shelfNavs(all []books.Book) []opdsNav {
+	counts := make(map[string]int)
+	for _, b := range all {
+		if shelf := books.NormalizeShelf(b.Shelf); shelf != "" {
+			counts[shelf]++
+		}
+		for _, tag := range b.GetTags() {
+			counts[tag]++
+		}
+	}
+	var navs []opdsNav
+	for shelf, count := range counts {
+		navs = append(navs, opdsNav{title: shelf, path: "/shelves/" + url.PathEscape(shelf), count: count})
+	}
+	sort.Slice(navs, func(i, j int) bool { return navs[i].title < navs[j].title })
+	return navs
+}
+
+// yearNavs lists every year with finished reads, newest first
+func yearNavs(all []books.Book) []opdsNav {
+	counts := make(map[int]int)
+	for _, read := range books.RecentlyFinished(all, "", 0) {
+		counts[read.Finished.Year()]++
+	}
+	var navs []opdsNav
+	for year, count := range counts {
+		navs = append(navs, opdsNav{title: strconv.Itoa(year), path: "/years/" + strconv.Itoa(year), count: count})
+	}
+	sort.Slice(navs, func(i, j int) bool { return navs[i].title > navs[j].title })
+	return navs
+}
+
+// creditedAuthors returns the names each book credits as an author
+func creditedAuthors(all []books.Book) [][]string {
+	names := make([][]string, len(all))
+	for i, b := range all {
+		additional, _ := b.AdditionalAuthors.(string)
+		for _, c := range books.ParseAuthorCredits(b.Author, additional) {
+			if c.Role == "author" {
+				names[i] = append(names[i], c.Name)
+			}
+		}
+	}
+	return names
+}
+
+// authorKeys groups every credited author and name (see books.AuthorKeys)
+func authorKeys(credited [][]string, name string) map[string]string {
+	all := []string{name}
+	for _, names := range credited {
+		all = append(all, names...)
+	}
+	return books.AuthorKeys(all)
+}
+
+// authorNavs lists every credited author by name
+func authorNavs(all []books.Book) []opdsNav {
+	credited := creditedAuthors(all)
+	keys := authorKeys(credited, "")
+	byKey := make(map[string]*opdsNav)
+	for _, names := range credited {
+		for _, name := range names {
+			key := keys[name]
+			if n, ok := byKey[key]; ok {
+				n.count++
+				continue
+			}
+			byKey[key] = &opdsNav{title: name, path: "/authors/" + url.PathEscape(name), count: 1}
+		}
+	}
+	navs := make([]opdsNav, 0, len(byKey))
+	for _, n := range byKey {
+		navs = append(navs, *n)
+	}
+	sort.Slice(navs, func(i, j int) bool { return strings.ToLower(navs[i].title) < strings.ToLower(navs[j].title) })
+	return navs
+}
+
+// booksByAuthor returns the books crediting name as an author
+func booksByAuthor(all []books.Book, name string) []books.Book {
+	credited := creditedAuthors(all)
+	keys := authorKeys(credited, name)
+	var result []books.Book
+	for i, names := range credited {
+		for _, n := range names {
+			if keys[n] == keys[name] {
+				result = append(result, all[i])
+				break
+			}
+		}
+	}
+	return result
+}
+
+// booksOnShelf returns books on an exclusive shelf or carrying a tag
+func booksOnShelf(all []books.Book, shelf string) []books.Book {
+	var result []books.Book
+	for _, b := range all {
+		if books.NormalizeShelf(b.Shelf) == shelf || b.HasTag(shelf) {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+// searchBooks returns books matching q, using the full-text index when the
+// store is available
+func searchBooks(all []books.Book, q string) ([]books.Book, error) {
+	if dataStore == nil {
+		q = strings.ToLower(q)
+		var result []books.Book
+		for _, b := range all {
+			if strings.Contains(strings.ToLower(b.GetTitle()), q) || strings.Contains(strings.ToLower(b.Author), q) {
+				result = append(result, b)
+			}
+		}
+		return result, nil
+	}
+
+	results, err := dataStore.Search(q, 100)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]books.Book, len(all))
+	for _, b := range all {
+		byID[b.ID] = b
+	}
+	seen := make(map[int64]bool)
+	var result []books.Book
+	for _, res := range results {
+		if b, ok := byID[res.BookID]; ok && !seen[res.BookID] {
+			seen[res.BookID] = true
+			result = append(result, b)
+		}
+	}
+	return result, nil
+}
+
+// GetOPDS handles the OPDS 1.2 catalog under /opds: navigation feeds of
+// shelves, years and authors, their book feeds, search and the OpenSearch
+// description
+func GetOPDS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	base := baseURL(r)
+	now := time.Now().UTC().Format(time.RFC3339)
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/opds"), "/")
+
+	if path == "/opensearch.xml" {
+		writeOPDS(w, openSearchType, openSearchDescription{
+			ShortName:   "Reading Tracker",
+			Description: "Search the books we've read, want to read and own",
+			InputEnc:    "UTF-8",
+			URLs: []openSearchURL{
+				{Type: opdsAcquisition, Template: base + "/opds/search?q={searchTerms}"},
+				{Type: "application/json", Template: base + "/api/search?q={searchTerms}"},
+			},
+		})
+		return
+	}
+
+	all := getBooks()
+	// r.URL.Path is already unescaped
+	section, name, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+
+	var list []books.Book
+	var title string
+	switch {
+	case section == "":
+		writeOPDS(w, opdsNavigation, navigationFeed(base, "", "Reading Tracker", []opdsNav{
+			{title: "Shelves", path: "/shelves", navigation: true},
+			{title: "Years", path: "/years", navigation: true},
+			{title: "Authors", path: "/authors", navigation: true},
+		}, now))
+		return
+	case section == "shelves" && name == "":
+		writeOPDS(w, opdsNavigation, navigationFeed(base, "/shelves", "Shelves", shelfNavs(all), now))
+		return
+	case section == "years" && name == "":
+		writeOPDS(w, opdsNavigation, navigationFeed(base, "/years", "Years", yearNavs(all), now))
+		return
+	case section == "authors" && name == "":
+		writeOPDS(w, opdsNavigation, navigationFeed(base, "/authors", "Authors", authorNavs(all), now))
+		return
+	case section == "shelves":
+		shelf := books.NormalizeShelf(name)
+		list, title = booksOnShelf(all, shelf), "Shelf: "+shelf
+	case section == "years":
+		year, err := strconv.Atoi(name)
+		if err != nil {
+			http.Error(w, "invalid year parameter", http.StatusBadRequest)
+			return
+		}
+		filtered, _ := books.FilterByYear(all, year)
+		list, title = books.FilterByShelf(filtered, "read"), fmt.Sprintf("Read in %d", year)
+	case section == "authors":
+		list, title = booksByAuthor(all, name), name
+	case section == "search" && name == "":
+		q := r.URL.Query().Get("q")
+		if q == "" {
+			http.Error(w, "Query parameter q is required", http.StatusBadRequest)
+			return
+		}
+		var err error
+		if list, err = searchBooks(all, q); err != nil {
+			http.Error(w, "Failed to search", http.StatusInternalServerError)
+			return
+		}
+		title = "Search: " + q
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	// Links and the feed ID need the path as it was sent, escapes included
+	feed, ok := bookFeed(r, base, r.URL.EscapedPath(), title, list, now)
+	if !ok {
+		http.Error(w, "invalid page parameter", http.StatusBadRequest)
+		return
+	}
+	writeOPDS(w, opdsAcquisition, feed)
+}
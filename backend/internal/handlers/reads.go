@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+	"github.com/kristenwomack/reading-app/backend/internal/store"
+)
+
+// readResponse is one read of a book
+type readResponse struct {
+	ID           int64  `json:"id"`
+	DateStarted  string `json:"dateStarted,omitempty"`
+	DateFinished string `json:"dateFinished,omitempty"`
+	Format       string `json:"format,omitempty"`
+	Notes        string `json:"notes,omitempty"`
+}
+
+// parseReadsPath extracts the book ID and optional read ID from
+// /api/books/{id}/reads[/{readId}]
+func parseReadsPath(path string) (bookID, readID int64, err error) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/books/"), "/"), "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[1] != "reads" {
+		return 0, 0, fmt.Errorf("invalid path")
+	}
+	bookID, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(parts) == 3 {
+		readID, err = strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return bookID, readID, nil
+}
+
+// GetReads handles GET /api/books/{id}/reads
+func GetReads(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	bookID, _, err := parseReadsPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+
+	reads, err := dataStore.GetReads(bookID)
+	if err != nil {
+		http.Error(w, "Failed to get reads", http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]readResponse, len(reads))
+	for i, rd := range reads {
+		result[i] = readResponse{
+			ID:           rd.ID,
+			DateStarted:  rd.DateStarted,
+			DateFinished: rd.DateFinished,
+			Format:       rd.Format,
+			Notes:        rd.Notes,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"reads": result})
+}
+
+// AddRead handles POST /api/books/{id}/reads
+func AddRead(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	bookID, _, err := parseReadsPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		DateStarted  string `json:"dateStarted"`
+		DateFinished string `json:"dateFinished"`
+		Format       string `json:"format"`
+		Notes        string `json:"notes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	for _, date := range []*string{&req.DateStarted, &req.DateFinished} {
+		if *date, err = normalizeDate(*date); err != nil {
+			http.Error(w, "Invalid date", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Format != "" && books.NormalizeFormat(req.Format) == "" {
+		http.Error(w, "format must be print, ebook or audiobook", http.StatusBadRequest)
+		return
+	}
+
+	book, err := dataStore.GetBook(bookID)
+	if err != nil {
+		http.Error(w, "Failed to get book", http.StatusInternalServerError)
+		return
+	}
+	if book == nil {
+		http.Error(w, "Book not found", http.StatusNotFound)
+		return
+	}
+
+	id, err := dataStore.AddRead(&store.Read{
+		BookID:       bookID,
+		DateStarted:  req.DateStarted,
+		DateFinished: req.DateFinished,
+		Format:       books.NormalizeFormat(req.Format),
+		Notes:        req.Notes,
+	})
+	if err != nil {
+		http.Error(w, "Failed to add read", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]int64{"id": id})
+}
+
+// DeleteRead handles DELETE /api/books/{id}/reads/{readId}
+func DeleteRead(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	bookID, readID, err := parseReadsPath(r.URL.Path)
+	if err != nil || readID == 0 {
+		http.Error(w, "Invalid read ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := dataStore.DeleteRead(bookID, readID); err != nil {
+		http.Error(w, "Failed to delete read", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// reviewBook is a book called out in the year in review
+type reviewBook struct {
+	Title    string  `json:"title"`
+	Author   string  `json:"author"`
+	Pages    int     `json:"pages,omitempty"`
+	DateRead string  `json:"dateRead,omitempty"`
+	Rating   float64 `json:"rating,omitempty"`
+	CoverURL string  `json:"coverUrl,omitempty"`
+}
+
+// reviewResponse is the year in review, rendered as JSON or HTML
+type reviewResponse struct {
+	Year         int                   `json:"year"`
+	Books        int                   `json:"books"`
+	Pages        int                   `json:"pages"`
+	AvgPages     float64               `json:"avgPages"`
+	Goal         *reviewGoal           `json:"goal"`
+	First        *reviewBook           `json:"firstBook"`
+	Last         *reviewBook           `json:"lastBook"`
+	Longest      *reviewBook           `json:"longestBook"`
+	Shortest     *reviewBook           `json:"shortestBook"`
+	TopRated     []reviewBook          `json:"topRated"`
+	AvgRating    float64               `json:"avgRating"`
+	BusiestMonth *monthTotalResponse   `json:"busiestMonth"`
+	Months       []monthTotalResponse  `json:"months"`
+	Authors      reviewAuthors         `json:"authors"`
+	Shelves      []reviewShelfResponse `json:"shelves"`
+	GeneratedAt  string                `json:"generatedAt"`
+}
+
+// reviewGoal is the year's goal and whether it was met
+type reviewGoal struct {
+	Target int    `json:"target"`
+	Status string `json:"status"`
+}
+
+// reviewAuthors summarises who we read
+type reviewAuthors struct {
+	Count     int                  `json:"count"`
+	New       int                  `json:"new"`
+	Returning int                  `json:"returning"`
+	Top       []reviewAuthorResult `json:"top"`
+}
+
+// reviewAuthorResult is one of the year's most-read authors
+type reviewAuthorResult struct {
+	Author string `json:"author"`
+	Books  int    `json:"books"`
+	Pages  int    `json:"pages"`
+}
+
+// reviewShelfResponse counts books read under a shelf or tag
+type reviewShelfResponse struct {
+	Shelf string `json:"shelf"`
+	Count int    `json:"count"`
+}
+
+// toReviewBook converts a book, nil when there is none
+func toReviewBook(b *books.Book) *reviewBook {
+	if b == nil {
+		return nil
+	}
+	return &reviewBook{
+		Title:    b.GetTitle(),
+		Author:   b.Author,
+		Pages:    b.GetPages(),
+		DateRead: b.DateRead,
+		Rating:   b.GetRating(),
+		CoverURL: getCoverURL(*b),
+	}
+}
+
+// buildReviewResponse converts a books.YearReview for rendering
+func buildReviewResponse(review books.YearReview, now time.Time) reviewResponse {
+	resp := reviewResponse{
+		Year:         review.Year,
+		Books:        review.Books,
+		Pages:        review.Pages,
+		AvgPages:     review.AvgPages,
+		First:        toReviewBook(review.First),
+		Last:         toReviewBook(review.Last),
+		Longest:      toReviewBook(review.Longest),
+		Shortest:     toReviewBook(review.Shortest),
+		TopRated:     []reviewBook{},
+		AvgRating:    review.AvgRating,
+		BusiestMonth: toMonthTotalResponse(review.BusiestMonth),
+		Months:       make([]monthTotalResponse, len(review.Months)),
+		Authors: reviewAuthors{
+			Count:     review.Authors,
+			New:       review.NewAuthors,
+			Returning: review.ReturningAuthors,
+			Top:       make([]reviewAuthorResult, len(review.TopAuthors)),
+		},
+		Shelves:     make([]reviewShelfResponse, len(review.Shelves)),
+		GeneratedAt: now.Format(time.RFC3339),
+	}
+	if review.GoalTarget > 0 {
+		resp.Goal = &reviewGoal{Target: review.GoalTarget, Status: review.GoalStatus}
+	}
+	for i := range review.TopRated {
+		resp.TopRated = append(resp.TopRated, *toReviewBook(&review.TopRated[i]))
+	}
+	for i, m := range review.Months {
+		resp.Months[i] = monthTotalResponse{Year: m.Year, Month: m.Month, Books: m.Books, Pages: m.Pages}
+	}
+	for i, a := range review.TopAuthors {
+		resp.Authors.Top[i] = reviewAuthorResult{Author: a.Author, Books: a.Books, Pages: a.Pages}
+	}
+	for i, s := range review.Shelves {
+		resp.Shelves[i] = reviewShelfResponse{Shelf: s.Shelf, Count: s.Count}
+	}
+	return resp
+}
+
+// GetYearReview handles GET /api/review/{year}. Add format=html for a
+// printable page rendered from the same data.
+func GetYearReview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	year, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/review/"))
+	if err != nil {
+		http.Error(w, "invalid year parameter", http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "html" {
+		http.Error(w, "invalid format parameter", http.StatusBadRequest)
+		return
+	}
+
+	goal := 0
+	if dataStore != nil {
+		g, err := dataStore.GetGoal(year)
+		if err != nil {
+			http.Error(w, "Failed to get goal", http.StatusInternalServerError)
+			return
+		}
+		if g != nil {
+			goal = g.BookTarget
+		}
+	}
+
+	now := time.Now()
+	review := buildReviewResponse(books.CalculateYearReview(getBooks(), year, goal, now), now)
+
+	if format == "html" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := reviewTemplate.Execute(w, review); err != nil {
+			http.Error(w, "Failed to render review", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// reviewTemplate is the printable year in review
+var reviewTemplate = template.Must(template.New("review").Funcs(template.FuncMap{
+	"monthName": func(m int) string { return time.Month(m).String() },
+	"stars": func(r float64) string {
+		n := books.WholeStars(r)
+		return strings.Repeat("★", n) + strings.Repeat("☆", 5-n)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Year}} in Review</title>
+<style>
+body { font-family: Georgia, serif; max-width: 46rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+h1 { margin-bottom: 0; }
+h2 { border-bottom: 1px solid #ccc; padding-bottom: .2rem; margin-top: 2rem; }
+.totals { display: flex; gap: 2rem; margin: 1.5rem 0; }
+.totals div { text-align: center; }
+.totals strong { display: block; font-size: 2rem; }
+table { border-collapse: collapse; width: 100%; }
+td, th { text-align: left; padding: .25rem .5rem; }
+.book img { height: 5rem; float: left; margin-right: .75rem; }
+.book { overflow: hidden; margin-bottom: .75rem; }
+.muted { color: #777; }
+@media print { body { margin: 0; } h2 { break-after: avoid; } .book { break-inside: avoid; } }
+</style>
+</head>
+<body>
+<h1>{{.Year}} in Review</h1>
+<p class="muted">Generated {{.GeneratedAt}}</p>
+
+<div class="totals">
+<div><strong>{{.Books}}</strong>books</div>
+<div><strong>{{.Pages}}</strong>pages</div>
+<div><strong>{{printf "%.0f" .AvgPages}}</strong>pages per book</div>
+<div><strong>{{.Authors.Count}}</strong>authors</div>
+</div>
+
+{{with .Goal}}<p>Goal: {{.Target}} books ({{.Status}}).</p>{{end}}
+{{if .BusiestMonth}}<p>Busiest month: {{monthName .BusiestMonth.Month}} with {{.BusiestMonth.Books}} books.</p>{{end}}
+
+<h2>Highlights</h2>
+{{define "book"}}<div class="book">{{if .CoverURL}}<img src="{{.CoverURL}}" alt="">{{end}}<strong>{{.Title}}</strong><br>{{.Author}}{{if .Pages}}, {{.Pages}} pages{{end}}{{if .DateRead}}<br><span class="muted">Finished {{.DateRead}}</span>{{end}}</div>{{end}}
+{{with .First}}<h3>First book</h3>{{template "book" .}}{{end}}
+{{with .Last}}<h3>Last book</h3>{{template "book" .}}{{end}}
+{{with .Longest}}<h3>Longest</h3>{{template "book" .}}{{end}}
+{{with .Shortest}}<h3>Shortest</h3>{{template "book" .}}{{end}}
+
+{{if .TopRated}}<h2>Top rated</h2>
+<p class="muted">Average rating {{printf "%.2f" .AvgRating}}</p>
+{{range .TopRated}}<div class="book"><strong>{{.Title}}</strong> by {{.Author}} {{stars .Rating}}</div>
+{{end}}{{end}}
+
+<h2>Month by month</h2>
+<table>
+<tr><th>Month</th><th>Books</th><th>Pages</th></tr>
+{{range .Months}}<tr><td>{{monthName .Month}}</td><td>{{.Books}}</td><td>{{.Pages}}</td></tr>
+{{end}}</table>
+
+<h2>Authors</h2>
+<p>{{.Authors.New}} new, {{.Authors.Returning}} returning.</p>
+{{if .Authors.Top}}<ol>
+{{range .Authors.Top}}<li>{{.Author}}: {{.Books}} books, {{.Pages}} pages</li>
+{{end}}</ol>{{end}}
+
+{{if .Shelves}}<h2>Shelves</h2>
+<ul>
+{{range .Shelves}}<li>{{.Shelf}}: {{.Count}}</li>
+{{end}}</ul>{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// searchResultResponse is a book or note matching a search
+type searchResultResponse struct {
+	Kind    string `json:"kind"`
+	ID      int64  `json:"id"`
+	BookID  int64  `json:"bookId"`
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+}
+
+// Search handles GET /api/search?q=...&limit=N
+func Search(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		http.Error(w, "Query parameter q is required", http.StatusBadRequest)
+		return
+	}
+
+	limit := 20
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > 100 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	results, err := dataStore.Search(q, limit)
+	if err != nil {
+		http.Error(w, "Failed to search", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]searchResultResponse, len(results))
+	for i, res := range results {
+		response[i] = searchResultResponse{
+			Kind:    res.Kind,
+			ID:      res.RefID,
+			BookID:  res.BookID,
+			Title:   res.Title,
+			Snippet: res.Snippet,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"query":   q,
+		"results": response,
+	})
+}
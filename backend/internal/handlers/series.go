@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// seriesEntryResponse is one book within a series listing
+type seriesEntryResponse struct {
+	ID       int64   `json:"id,omitempty"`
+	Title    string  `json:"title"`
+	Author   string  `json:"author"`
+	Position float64 `json:"position,omitempty"`
+	Shelf    string  `json:"shelf"`
+	DateRead string  `json:"dateRead,omitempty"`
+}
+
+// seriesResponse summarises one series
+type seriesResponse struct {
+	Name   string                `json:"name"`
+	Read   []seriesEntryResponse `json:"read"`
+	Unread []seriesEntryResponse `json:"unread"`
+	NextUp *seriesEntryResponse  `json:"nextUp"`
+}
+
+// toSeriesEntry converts a books.SeriesEntry for the API
+func toSeriesEntry(e books.SeriesEntry) seriesEntryResponse {
+	return seriesEntryResponse{
+		ID:       e.Book.ID,
+		Title:    e.Book.GetTitle(),
+		Author:   e.Book.Author,
+		Position: e.Position,
+		Shelf:    e.Book.Shelf,
+		DateRead: e.Book.DateRead,
+	}
+}
+
+// GetSeries handles GET /api/series
+func GetSeries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	grouped := books.GroupBySeries(getBooks())
+
+	result := make([]seriesResponse, 0, len(grouped))
+	for _, s := range grouped {
+		sr := seriesResponse{
+			Name:   s.Name,
+			Read:   []seriesEntryResponse{},
+			Unread: []seriesEntryResponse{},
+		}
+		for _, e := range s.Entries {
+			if e.Book.Shelf == "read" {
+				sr.Read = append(sr.Read, toSeriesEntry(e))
+			} else {
+				sr.Unread = append(sr.Unread, toSeriesEntry(e))
+			}
+		}
+		if s.NextUp != nil {
+			next := toSeriesEntry(*s.NextUp)
+			sr.NextUp = &next
+		}
+		result = append(result, sr)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"series": result})
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// yearSummaryResponse is one year in the stats summary
+type yearSummaryResponse struct {
+	Year       int     `json:"year"`
+	Books      int     `json:"books"`
+	Pages      int     `json:"pages"`
+	AvgPages   float64 `json:"avgPages"`
+	GoalTarget int     `json:"goalTarget,omitempty"`
+	GoalStatus string  `json:"goalStatus,omitempty"`
+	BooksDelta int     `json:"booksDelta"`
+	PagesDelta int     `json:"pagesDelta"`
+}
+
+// monthTotalResponse is a single month's reading
+type monthTotalResponse struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Books int `json:"books"`
+	Pages int `json:"pages"`
+}
+
+// toMonthTotalResponse converts a month total, nil when there is none
+func toMonthTotalResponse(m *books.MonthTotal) *monthTotalResponse {
+	if m == nil {
+		return nil
+	}
+	return &monthTotalResponse{Year: m.Year, Month: m.Month, Books: m.Books, Pages: m.Pages}
+}
+
+// GetStatsSummary handles GET /api/stats/summary
+func GetStatsSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	goals := map[int]int{}
+	if dataStore != nil {
+		var err error
+		if goals, err = dataStore.GetGoals(); err != nil {
+			http.Error(w, "Failed to get goals", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	summary := books.CalculateSummary(getBooks(), goals, time.Now())
+
+	years := make([]yearSummaryResponse, len(summary.Years))
+	for i, y := range summary.Years {
+		years[i] = yearSummaryResponse{
+			Year:       y.Year,
+			Books:      y.Books,
+			Pages:      y.Pages,
+			AvgPages:   y.AvgPages,
+			GoalTarget: y.GoalTarget,
+			GoalStatus: y.GoalStatus,
+			BooksDelta: y.BooksDelta,
+			PagesDelta: y.PagesDelta,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"years": years,
+		"lifetime": map[string]interface{}{
+			"books":           summary.TotalBooks,
+			"pages":           summary.TotalPages,
+			"avgPages":        summary.AvgPages,
+			"avgBooksPerYear": summary.AvgBooksYear,
+			"bestYear":        summary.BestYear,
+			"goalStreak":      summary.LongestStreak,
+		},
+		"bestMonth":  toMonthTotalResponse(summary.BestMonth),
+		"worstMonth": toMonthTotalResponse(summary.WorstMonth),
+	})
+}
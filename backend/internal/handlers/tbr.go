@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// pickResponse is a to-read book suggested by the picker
+type pickResponse struct {
+	ID        int64    `json:"id,omitempty"`
+	Title     string   `json:"title"`
+	Author    string   `json:"author"`
+	Pages     int      `json:"pages,omitempty"`
+	DateAdded string   `json:"dateAdded,omitempty"`
+	Score     float64  `json:"score"`
+	Reasons   []string `json:"reasons"`
+}
+
+// GetTBRPick handles GET /api/tbr/pick. Query parameters: count, seed,
+// minPages, maxPages, tags (comma-separated), recentMonths and the weights
+// weightAge, weightPages, weightAuthor, weightSeries and weightTags.
+func GetTBRPick(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	opts := books.PickOptions{
+		Weights: books.DefaultPickWeights,
+		Tags:    books.ParseTags(q.Get("tags")),
+		Count:   5,
+		Seed:    time.Now().UnixNano(),
+	}
+
+	ints := map[string]*int{
+		"count":        &opts.Count,
+		"minPages":     &opts.MinPages,
+		"maxPages":     &opts.MaxPages,
+		"recentMonths": &opts.RecentMonths,
+	}
+	for name, dest := range ints {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				http.Error(w, "invalid "+name+" parameter", http.StatusBadRequest)
+				return
+			}
+			*dest = n
+		}
+	}
+	if opts.Count < 1 || opts.Count > 50 {
+		http.Error(w, "count must be 1-50", http.StatusBadRequest)
+		return
+	}
+
+	weights := map[string]*float64{
+		"weightAge":    &opts.Weights.Age,
+		"weightPages":  &opts.Weights.Pages,
+		"weightAuthor": &opts.Weights.Author,
+		"weightSeries": &opts.Weights.Series,
+		"weightTags":   &opts.Weights.Tags,
+	}
+	for name, dest := range weights {
+		if v := q.Get(name); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil || f < 0 {
+				http.Error(w, "invalid "+name+" parameter", http.StatusBadRequest)
+				return
+			}
+			*dest = f
+		}
+	}
+
+	if v := q.Get("seed"); v != "" {
+		seed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid seed parameter", http.StatusBadRequest)
+			return
+		}
+		opts.Seed = seed
+	}
+
+	picks := books.PickTBR(getBooks(), opts)
+	result := make([]pickResponse, len(picks))
+	for i, p := range picks {
+		reasons := p.Reasons
+		if reasons == nil {
+			reasons = []string{}
+		}
+		result[i] = pickResponse{
+			ID:        p.Book.ID,
+			Title:     p.Book.GetTitle(),
+			Author:    p.Book.Author,
+			Pages:     p.Book.GetPages(),
+			DateAdded: p.Book.GetDateAdded(),
+			Score:     math.Round(p.Score*100) / 100,
+			Reasons:   reasons,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"seed":  strconv.FormatInt(opts.Seed, 10),
+		"picks": result,
+	})
+}
+
+// GetTBRStats handles GET /api/stats/tbr
+func GetTBRStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report := books.CalculateTBRReport(getBooks(), time.Now())
+
+	type monthResponse struct {
+		Month     string `json:"month"`
+		Added     int    `json:"added"`
+		Completed int    `json:"completed"`
+		Size      int    `json:"size"`
+	}
+	history := make([]monthResponse, len(report.History))
+	for i, m := range report.History {
+		history[i] = monthResponse{Month: m.Month, Added: m.Added, Completed: m.Completed, Size: m.Size}
+	}
+
+	type bucketResponse struct {
+		Label string `json:"label"`
+		Count int    `json:"count"`
+	}
+	buckets := make([]bucketResponse, len(report.AgeBuckets))
+	for i, b := range report.AgeBuckets {
+		buckets[i] = bucketResponse{Label: b.Label, Count: b.Count}
+	}
+
+	type oldestResponse struct {
+		ID        int64  `json:"id,omitempty"`
+		Title     string `json:"title"`
+		Author    string `json:"author"`
+		DateAdded string `json:"dateAdded"`
+		AgeDays   int    `json:"ageDays"`
+	}
+	oldest := make([]oldestResponse, len(report.Oldest))
+	for i, e := range report.Oldest {
+		oldest[i] = oldestResponse{
+			ID:        e.Book.ID,
+			Title:     e.Book.GetTitle(),
+			Author:    e.Book.Author,
+			DateAdded: e.DateAdded,
+			AgeDays:   e.AgeDays,
+		}
+	}
+
+	// A backlog that isn't shrinking has no projected clear date
+	var yearsToClear interface{}
+	if report.YearsToClear > 0 {
+		yearsToClear = report.YearsToClear
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"size":               report.Size,
+		"history":            history,
+		"ageBuckets":         buckets,
+		"oldest":             oldest,
+		"medianAgeDays":      report.MedianAgeDays,
+		"additionsPerYear":   report.AdditionsPerYear,
+		"completionsPerYear": report.CompletionsPerYear,
+		"yearsToClear":       yearsToClear,
+	})
+}
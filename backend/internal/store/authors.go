@@ -0,0 +1,396 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// Author is a normalized person credited on one or more books
+type Author struct {
+	ID        int64
+	Name      string
+	Aliases   []string
+	BooksRead int
+	Pages     int
+	FirstRead string
+	LastRead  string
+}
+
+// AuthorBook is a book credited to an author along with their role
+type AuthorBook struct {
+	Book
+	Role string
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// migrateAuthors creates the author tables and links existing books
+func migrateAuthors(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		CREATE TABLE authors (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL
+		);
+
+		CREATE TABLE author_aliases (
+			alias_key TEXT PRIMARY KEY,
+			author_id INTEGER NOT NULL REFERENCES authors(id) ON DELETE CASCADE,
+			name TEXT NOT NULL
+		);
+		CREATE INDEX idx_author_aliases_author ON author_aliases(author_id);
+
+		CREATE TABLE book_authors (
+			book_id INTEGER NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+			author_id INTEGER NOT NULL REFERENCES authors(id) ON DELETE CASCADE,
+			role TEXT NOT NULL DEFAULT 'author',
+			PRIMARY KEY (book_id, author_id, role)
+		);
+		CREATE INDEX idx_book_authors_author ON book_authors(author_id);
+	`); err != nil {
+		return err
+	}
+	return linkAllBooks(tx)
+}
+
+// linkAllBooks links every book to its credited authors
+func linkAllBooks(tx *sql.Tx) error {
+	rows, err := tx.Query("SELECT id, author, additional_authors FROM books")
+	if err != nil {
+		return err
+	}
+	type credited struct {
+		id                 int64
+		author, additional string
+	}
+	var all []credited
+	for rows.Next() {
+		var c credited
+		if err := rows.Scan(&c.id, &c.author, &c.additional); err != nil {
+			rows.Close()
+			return err
+		}
+		all = append(all, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range all {
+		if err := linkAuthors(tx, c.id, c.author, c.additional); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkAuthors replaces a book's author links with those parsed from its
+// credits. Authors the book no longer credits are removed if they have no
+// other books.
+func linkAuthors(q querier, bookID int64, author, additional string) error {
+	previous, err := bookAuthorIDs(q, bookID)
+	if err != nil {
+		return err
+	}
+	if _, err := q.Exec("DELETE FROM book_authors WHERE book_id = ?", bookID); err != nil {
+		return err
+	}
+	for _, credit := range books.ParseAuthorCredits(author, additional) {
+		authorID, err := findOrCreateAuthor(q, credit.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := q.Exec(`
+			INSERT OR IGNORE INTO book_authors (book_id, author_id, role) VALUES (?, ?, ?)
+		`, bookID, authorID, credit.Role); err != nil {
+			return err
+		}
+	}
+	return pruneAuthors(q, previous)
+}
+
+// bookAuthorIDs returns the authors credited on a book
+func bookAuthorIDs(q querier, bookID int64) ([]int64, error) {
+	rows, err := q.Query("SELECT DISTINCT author_id FROM book_authors WHERE book_id = ?", bookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// pruneAuthors removes those of the given authors left with no books, and
+// their aliases
+func pruneAuthors(q querier, ids []int64) error {
+	for _, id := range ids {
+		for _, stmt := range []string{
+			"DELETE FROM author_aliases WHERE author_id = ? AND NOT EXISTS (SELECT 1 FROM book_authors WHERE author_id = ?)",
+			"DELETE FROM authors WHERE id = ? AND NOT EXISTS (SELECT 1 FROM book_authors WHERE author_id = ?)",
+		} {
+			if _, err := q.Exec(stmt, id, id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// migratePruneAuthors removes authors left without books by renames and
+// deletions before authors were pruned as they happened
+func migratePruneAuthors(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		DELETE FROM author_aliases WHERE author_id NOT IN (SELECT author_id FROM book_authors);
+		DELETE FROM authors WHERE id NOT IN (SELECT author_id FROM book_authors);
+	`)
+	return err
+}
+
+// findOrCreateAuthor returns the author matching name, creating one if
+// needed. A name that isn't a known alias joins the author it matches
+// (see books.AuthorKeys) and becomes one of their aliases.
+func findOrCreateAuthor(q querier, name string) (int64, error) {
+	key := books.NormalizeAuthor(name)
+
+	var id int64
+	err := q.QueryRow("SELECT author_id FROM author_aliases WHERE alias_key = ?", key).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	id, err = matchAuthor(q, name)
+	if err != nil {
+		return 0, err
+	}
+	if id == 0 {
+		result, err := q.Exec("INSERT INTO authors (name) VALUES (?)", name)
+		if err != nil {
+			return 0, err
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := q.Exec("INSERT INTO author_aliases (alias_key, author_id, name) VALUES (?, ?, ?)", key, id, name); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// matchAuthor returns the author with an alias differing from name only in
+// middle initials that one of them leaves out, or 0 when there is none or
+// the initials of the aliases leave it ambiguous
+func matchAuthor(q querier, name string) (int64, error) {
+	words := strings.Fields(books.NormalizeAuthor(name))
+	if len(words) < 2 {
+		return 0, nil
+	}
+	rows, err := q.Query(`
+		SELECT author_id, name FROM author_aliases
+		WHERE alias_key LIKE ? AND alias_key LIKE ?
+		ORDER BY author_id
+	`, words[0]+" %", "% "+words[len(words)-1])
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	names := []string{name}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		var alias string
+		if err := rows.Scan(&id, &alias); err != nil {
+			return 0, err
+		}
+		names = append(names, alias)
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	keys := books.AuthorKeys(names)
+	for i, id := range ids {
+		if keys[names[i+1]] == keys[name] {
+			return id, nil
+		}
+	}
+	return 0, nil
+}
+
+// migrateAuthorInitials keys aliases by their middle initials too, and
+// relinks every book so names with different initials, merged before,
+// become separate authors. Merges made by hand keep their aliases and stay.
+func migrateAuthorInitials(tx *sql.Tx) error {
+	rows, err := tx.Query("SELECT alias_key, name FROM author_aliases")
+	if err != nil {
+		return err
+	}
+	rekeyed := make(map[string]string)
+	for rows.Next() {
+		var key, name string
+		if err := rows.Scan(&key, &name); err != nil {
+			rows.Close()
+			return err
+		}
+		rekeyed[key] = books.NormalizeAuthor(name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for old, key := range rekeyed {
+		if _, err := tx.Exec("UPDATE author_aliases SET alias_key = ? WHERE alias_key = ?", key, old); err != nil {
+			return err
+		}
+	}
+	return linkAllBooks(tx)
+}
+
+// authorStatsQuery aggregates read books per author
+const authorStatsQuery = `
+	SELECT a.id, a.name,
+	       COUNT(DISTINCT CASE WHEN b.shelf = 'read' THEN b.id END),
+	       COALESCE(SUM(CASE WHEN b.shelf = 'read' THEN b.pages END), 0),
+	       COALESCE(MIN(CASE WHEN b.shelf = 'read' AND b.date_read != '' THEN b.date_read END), ''),
+	       COALESCE(MAX(CASE WHEN b.shelf = 'read' AND b.date_read != '' THEN b.date_read END), '')
+	FROM authors a
+	LEFT JOIN (SELECT DISTINCT book_id, author_id FROM book_authors) ba ON ba.author_id = a.id
+	LEFT JOIN books b ON b.id = ba.book_id
+`
+
+// scanAuthor reads a row produced by authorStatsQuery
+func scanAuthor(row rowScanner) (Author, error) {
+	var a Author
+	err := row.Scan(&a.ID, &a.Name, &a.BooksRead, &a.Pages, &a.FirstRead, &a.LastRead)
+	return a, err
+}
+
+// GetAuthors returns all authors with their reading stats, ordered by name
+func (s *Store) GetAuthors() ([]Author, error) {
+	rows, err := s.db.Query(authorStatsQuery + ` GROUP BY a.id ORDER BY a.name COLLATE NOCASE`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var authors []Author
+	for rows.Next() {
+		a, err := scanAuthor(rows)
+		if err != nil {
+			return nil, err
+		}
+		authors = append(authors, a)
+	}
+	return authors, rows.Err()
+}
+
+// GetAuthor returns a single author with stats and aliases, or nil if not found
+func (s *Store) GetAuthor(id int64) (*Author, error) {
+	a, err := scanAuthor(s.db.QueryRow(authorStatsQuery+` WHERE a.id = ? GROUP BY a.id`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query("SELECT name FROM author_aliases WHERE author_id = ? ORDER BY name", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, err
+		}
+		a.Aliases = append(a.Aliases, alias)
+	}
+	return &a, rows.Err()
+}
+
+// GetAuthorBooks returns the books credited to an author
+func (s *Store) GetAuthorBooks(authorID int64) ([]AuthorBook, error) {
+	rows, err := s.db.Query(`
+		SELECT `+bookColumns+`, ba.role
+		FROM book_authors ba JOIN books b ON b.id = ba.book_id
+		WHERE ba.author_id = ?
+		ORDER BY b.date_read DESC, b.title
+	`, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []AuthorBook
+	for rows.Next() {
+		var role string
+		b, err := scanBook(rows, &role)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, AuthorBook{Book: b, Role: role})
+	}
+	return result, rows.Err()
+}
+
+// MergeAuthors folds source into target: books and aliases move to target
+// and source is deleted
+func (s *Store) MergeAuthors(sourceID, targetID int64) error {
+	if sourceID == targetID {
+		return fmt.Errorf("cannot merge an author into itself")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, id := range []int64{sourceID, targetID} {
+		var exists int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM authors WHERE id = ?", id).Scan(&exists); err != nil {
+			return err
+		}
+		if exists == 0 {
+			return fmt.Errorf("author %d not found", id)
+		}
+	}
+
+	// Links the target already has would collide, so drop what is left over
+	if _, err := tx.Exec("UPDATE OR IGNORE book_authors SET author_id = ? WHERE author_id = ?", targetID, sourceID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM book_authors WHERE author_id = ?", sourceID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE author_aliases SET author_id = ? WHERE author_id = ?", targetID, sourceID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM authors WHERE id = ?", sourceID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,205 @@
+package store
+
+import (
+	"database/sql"
+	"testing"
+)
+
+// TestAuthorsNormalized verifies name variants link to one author
+func TestAuthorsNormalized(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	for _, b := range []Book{
+		{Title: "Kindred", Author: "Octavia E. Butler", Pages: 264, DateRead: "2023/02/01", Shelf: "read"},
+		{Title: "Dawn", Author: "Octavia Butler", Pages: 248, DateRead: "2024/05/10", Shelf: "read"},
+		{Title: "Fledgling", Author: "Octavia Butler", Pages: 310, Shelf: "to-read"},
+	} {
+		if _, err := s.CreateBook(&b); err != nil {
+			t.Fatalf("Failed to create book: %v", err)
+		}
+	}
+
+	authors, err := s.GetAuthors()
+	if err != nil {
+		t.Fatalf("Failed to get authors: %v", err)
+	}
+	if len(authors) != 1 {
+		t.Fatalf("Expected 1 author, got %d", len(authors))
+	}
+
+	a := authors[0]
+	if a.BooksRead != 2 {
+		t.Errorf("Expected 2 books read, got %d", a.BooksRead)
+	}
+	if a.Pages != 512 {
+		t.Errorf("Expected 512 pages, got %d", a.Pages)
+	}
+	if a.FirstRead != "2023/02/01" || a.LastRead != "2024/05/10" {
+		t.Errorf("Expected first/last read 2023/02/01 and 2024/05/10, got %s and %s", a.FirstRead, a.LastRead)
+	}
+
+	books, err := s.GetAuthorBooks(a.ID)
+	if err != nil {
+		t.Fatalf("Failed to get author books: %v", err)
+	}
+	if len(books) != 3 {
+		t.Errorf("Expected 3 credited books, got %d", len(books))
+	}
+}
+
+// TestMergeAuthors verifies books and aliases move to the target author
+func TestMergeAuthors(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	// Pen names don't normalize together, so they start as separate authors
+	if _, err := s.CreateBook(&Book{Title: "The Cuckoo's Calling", Author: "Robert Galbraith", Shelf: "read"}); err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+	if _, err := s.CreateBook(&Book{Title: "Casual Vacancy", Author: "J.K. Rowling", Shelf: "read"}); err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	authors, err := s.GetAuthors()
+	if err != nil || len(authors) != 2 {
+		t.Fatalf("Expected 2 authors, got %d (err %v)", len(authors), err)
+	}
+	target, source := authors[0], authors[1] // J.K. Rowling, Robert Galbraith
+
+	if err := s.MergeAuthors(source.ID, target.ID); err != nil {
+		t.Fatalf("Failed to merge authors: %v", err)
+	}
+
+	merged, err := s.GetAuthor(target.ID)
+	if err != nil {
+		t.Fatalf("Failed to get author: %v", err)
+	}
+	if merged.BooksRead != 2 {
+		t.Errorf("Expected 2 books after merge, got %d", merged.BooksRead)
+	}
+	if len(merged.Aliases) != 2 {
+		t.Errorf("Expected 2 aliases after merge, got %v", merged.Aliases)
+	}
+
+	gone, err := s.GetAuthor(source.ID)
+	if err != nil {
+		t.Fatalf("Failed to get author: %v", err)
+	}
+	if gone != nil {
+		t.Error("Expected source author to be deleted")
+	}
+
+	// New books under the pen name resolve to the merged author
+	if _, err := s.CreateBook(&Book{Title: "The Silkworm", Author: "Robert Galbraith", Shelf: "read"}); err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+	merged, _ = s.GetAuthor(target.ID)
+	if merged.BooksRead != 3 {
+		t.Errorf("Expected 3 books for merged author, got %d", merged.BooksRead)
+	}
+}
+
+// TestAuthorsPruned verifies authors left without books by a rename or a
+// deletion are removed
+func TestAuthorsPruned(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	id, _ := s.CreateBook(&Book{Title: "Kindred", Author: "Octavia Butler", Shelf: "read"})
+	other, _ := s.CreateBook(&Book{Title: "Dune", Author: "Frank Herbert", Shelf: "read"})
+
+	book, _ := s.GetBook(id)
+	book.Author = "Ursula K. Le Guin"
+	if err := s.UpdateBook(book); err != nil {
+		t.Fatalf("Failed to update book: %v", err)
+	}
+	if err := s.DeleteBook(other); err != nil {
+		t.Fatalf("Failed to delete book: %v", err)
+	}
+
+	authors, err := s.GetAuthors()
+	if err != nil {
+		t.Fatalf("Failed to get authors: %v", err)
+	}
+	if len(authors) != 1 || authors[0].Name != "Ursula K. Le Guin" {
+		t.Errorf("Expected only the renamed author, got %+v", authors)
+	}
+
+	// The old name is free to become a new author again
+	s.CreateBook(&Book{Title: "Dune Messiah", Author: "Frank Herbert", Shelf: "read"})
+	if authors, _ = s.GetAuthors(); len(authors) != 2 {
+		t.Errorf("Expected 2 authors, got %+v", authors)
+	}
+}
+
+// TestAuthorsDifferentInitials verifies names with different middle
+// initials stay separate authors, and a name without them only joins one
+// when that is unambiguous
+func TestAuthorsDifferentInitials(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	for _, b := range []Book{
+		{Title: "First", Author: "John A. Smith", Shelf: "read"},
+		{Title: "Second", Author: "John B. Smith", Shelf: "read"},
+		{Title: "Third", Author: "John Smith", Shelf: "read"},
+		{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"},
+		{Title: "Dawn", Author: "Octavia Butler", Shelf: "read"},
+	} {
+		if _, err := s.CreateBook(&b); err != nil {
+			t.Fatalf("Failed to create book: %v", err)
+		}
+	}
+
+	authors, err := s.GetAuthors()
+	if err != nil {
+		t.Fatalf("Failed to get authors: %v", err)
+	}
+	var names []string
+	for _, a := range authors {
+		names = append(names, a.Name)
+	}
+	if len(authors) != 4 {
+		t.Errorf("Expected 4 authors (three Smiths and one Butler), got %v", names)
+	}
+}
+
+// TestMigrateAuthorInitials verifies authors merged only because their
+// middle initials were ignored are split apart
+func TestMigrateAuthorInitials(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	s := &Store{db: db}
+	defer s.Close()
+
+	// Just before migrateAuthorInitials, with both Smiths under one key
+	if err := s.migrateTo(len(migrations) - 1); err != nil {
+		t.Fatalf("Failed to create old schema: %v", err)
+	}
+	first, _ := s.CreateBook(&Book{Title: "First", Author: "John A. Smith", Shelf: "read"})
+	second, _ := s.CreateBook(&Book{Title: "Second", Author: "John B. Smith", Shelf: "read"})
+	if _, err := db.Exec(`
+		UPDATE author_aliases SET alias_key = 'john smith' WHERE alias_key = 'john a smith';
+		DELETE FROM author_aliases WHERE alias_key = 'john b smith';
+		UPDATE book_authors SET author_id = (SELECT author_id FROM book_authors WHERE book_id = ?) WHERE book_id = ?;
+		DELETE FROM authors WHERE id NOT IN (SELECT author_id FROM book_authors);
+	`, first, second); err != nil {
+		t.Fatalf("Failed to set up merged authors: %v", err)
+	}
+	if authors, _ := s.GetAuthors(); len(authors) != 1 {
+		t.Fatalf("Expected the Smiths merged before migrating, got %d authors", len(authors))
+	}
+
+	if err := s.migrate(); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	authors, _ := s.GetAuthors()
+	if len(authors) != 2 || authors[0].Name != "John A. Smith" || authors[1].Name != "John B. Smith" {
+		t.Errorf("Expected John A. Smith and John B. Smith, got %+v", authors)
+	}
+}
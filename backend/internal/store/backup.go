@@ -0,0 +1,453 @@
+package store
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Backup archive format. BackupVersion changes only when the archive
+// layout does; schema changes are tracked by SchemaVersion.
+const (
+	BackupFormat  = "reading-tracker-backup"
+	BackupVersion = 1
+)
+
+// ErrInvalidBackup is returned, wrapped, for an archive that cannot be restored
+var ErrInvalidBackup = errors.New("invalid backup")
+
+// BackupManifest describes a backup archive. It is stored as manifest.json
+// next to one tables/<name>.json file per table.
+type BackupManifest struct {
+	Format        string        `json:"format"`
+	Version       int           `json:"version"`
+	SchemaVersion int           `json:"schemaVersion"`
+	CreatedAt     string        `json:"createdAt"`
+	Tables        []BackupTable `json:"tables"`
+}
+
+// BackupTable lists a table's file with its row count and SHA-256 checksum
+type BackupTable struct {
+	Name   string `json:"name"`
+	Rows   int    `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
+
+// tableData is the content of one table
+type tableData struct {
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// RestoreResult summarises a restore
+type RestoreResult struct {
+	SchemaVersion int            // schema version of the backup, before upgrading
+	Tables        map[string]int // rows restored per table
+}
+
+// dataTables lists the tables holding data. The full-text index and its
+// shadow tables are left out: triggers rebuild them from books and notes.
+func dataTables(q querier) ([]string, error) {
+	rows, err := q.Query(`
+		SELECT name, COALESCE(sql, '') FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names, virtual []string
+	for rows.Next() {
+		var name, ddl string
+		if err := rows.Scan(&name, &ddl); err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(strings.ToUpper(ddl), "CREATE VIRTUAL TABLE") {
+			virtual = append(virtual, name)
+			continue
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var tables []string
+	for _, name := range names {
+		shadow := false
+		for _, v := range virtual {
+			shadow = shadow || strings.HasPrefix(name, v+"_")
+		}
+		if !shadow {
+			tables = append(tables, name)
+		}
+	}
+	return tables, nil
+}
+
+// tableColumns returns the columns of a table
+func tableColumns(q querier, table string) (map[string]bool, error) {
+	rows, err := q.Query(fmt.Sprintf(`SELECT name FROM pragma_table_info('%s')`, table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// dumpTables reads every data table
+func dumpTables(q querier) (map[string]*tableData, error) {
+	names, err := dataTables(q)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make(map[string]*tableData, len(names))
+	for _, name := range names {
+		rows, err := q.Query(fmt.Sprintf(`SELECT * FROM "%s"`, name))
+		if err != nil {
+			return nil, err
+		}
+		columns, err := rows.Columns()
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		data := &tableData{Columns: columns, Rows: [][]interface{}{}}
+		for rows.Next() {
+			values := make([]interface{}, len(columns))
+			ptrs := make([]interface{}, len(columns))
+			for i := range values {
+				ptrs[i] = &values[i]
+			}
+			if err := rows.Scan(ptrs...); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			for i, v := range values {
+				switch val := v.(type) {
+				case []byte:
+					values[i] = string(val)
+				case time.Time:
+					// The driver parses DATETIME columns; store them as
+					// CURRENT_TIMESTAMP writes them
+					values[i] = val.UTC().Format("2006-01-02 15:04:05")
+				}
+			}
+			data.Rows = append(data.Rows, values)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		tables[name] = data
+	}
+	return tables, nil
+}
+
+// Backup writes a zip archive of every table with a manifest recording the
+// schema version and a checksum per table. The tables are read in one
+// transaction, so the archive is a consistent snapshot.
+func (s *Store) Backup(w io.Writer) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var version int
+	if err := tx.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	tables, err := dumpTables(tx)
+	if err != nil {
+		return err
+	}
+
+	manifest := BackupManifest{
+		Format:        BackupFormat,
+		Version:       BackupVersion,
+		SchemaVersion: version,
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+	}
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		data, err := json.Marshal(tables[name])
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		manifest.Tables = append(manifest.Tables, BackupTable{
+			Name:   name,
+			Rows:   len(tables[name].Rows),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+		f, err := zw.Create("tables/" + name + ".json")
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(data); err != nil {
+			return err
+		}
+	}
+
+	f, err := zw.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// readBackup opens an archive and verifies its manifest and checksums
+func readBackup(archive []byte) (*BackupManifest, map[string]*tableData, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: not a zip archive", ErrInvalidBackup)
+	}
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	readFile := func(name string) ([]byte, error) {
+		f, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrInvalidBackup, name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidBackup, name, err)
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidBackup, name, err)
+		}
+		return data, nil
+	}
+
+	data, err := readFile("manifest.json")
+	if err != nil {
+		return nil, nil, err
+	}
+	var manifest BackupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("%w: manifest: %v", ErrInvalidBackup, err)
+	}
+	if manifest.Format != BackupFormat {
+		return nil, nil, fmt.Errorf("%w: not a reading tracker backup", ErrInvalidBackup)
+	}
+	if manifest.Version != BackupVersion {
+		return nil, nil, fmt.Errorf("%w: unsupported backup version %d", ErrInvalidBackup, manifest.Version)
+	}
+
+	tables := make(map[string]*tableData, len(manifest.Tables))
+	for _, t := range manifest.Tables {
+		data, err := readFile("tables/" + t.Name + ".json")
+		if err != nil {
+			return nil, nil, err
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != t.SHA256 {
+			return nil, nil, fmt.Errorf("%w: checksum mismatch for %s", ErrInvalidBackup, t.Name)
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var td tableData
+		if err := dec.Decode(&td); err != nil {
+			return nil, nil, fmt.Errorf("%w: %s: %v", ErrInvalidBackup, t.Name, err)
+		}
+		if len(td.Rows) != t.Rows {
+			return nil, nil, fmt.Errorf("%w: %s has %d rows, expected %d", ErrInvalidBackup, t.Name, len(td.Rows), t.Rows)
+		}
+		tables[t.Name] = &td
+	}
+	if _, ok := tables["books"]; !ok {
+		return nil, nil, fmt.Errorf("%w: missing books table", ErrInvalidBackup)
+	}
+	return &manifest, tables, nil
+}
+
+// replaceTables empties every data table and fills it from tables in one
+// transaction. Foreign keys are checked at commit, so tables load in any
+// order and a backup with dangling references changes nothing.
+func (s *Store) replaceTables(tables map[string]*tableData) (map[string]int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("PRAGMA defer_foreign_keys = ON"); err != nil {
+		return nil, err
+	}
+	names, err := dataTables(tx)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]map[string]bool, len(names))
+	for _, name := range names {
+		if known[name], err = tableColumns(tx, name); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate before changing anything; names are interpolated below
+	for name, data := range tables {
+		columns, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown table %s", ErrInvalidBackup, name)
+		}
+		for _, c := range data.Columns {
+			if !columns[c] {
+				return nil, fmt.Errorf("%w: unknown column %s.%s", ErrInvalidBackup, name, c)
+			}
+		}
+		for _, row := range data.Rows {
+			if len(row) != len(data.Columns) {
+				return nil, fmt.Errorf("%w: malformed row in %s", ErrInvalidBackup, name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM "%s"`, name)); err != nil {
+			return nil, err
+		}
+	}
+
+	counts := make(map[string]int, len(tables))
+	for name, data := range tables {
+		counts[name] = len(data.Rows)
+		if len(data.Columns) == 0 || len(data.Rows) == 0 {
+			continue
+		}
+		stmt, err := tx.Prepare(fmt.Sprintf(`INSERT INTO "%s" ("%s") VALUES (%s)`,
+			name, strings.Join(data.Columns, `", "`), strings.TrimSuffix(strings.Repeat("?, ", len(data.Columns)), ", ")))
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range data.Rows {
+			args := make([]interface{}, len(row))
+			for i, v := range row {
+				args[i] = backupValue(v)
+			}
+			if _, err := stmt.Exec(args...); err != nil {
+				stmt.Close()
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalidBackup, name, err)
+			}
+		}
+		stmt.Close()
+	}
+
+	var violations int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM pragma_foreign_key_check").Scan(&violations); err != nil {
+		return nil, err
+	}
+	if violations > 0 {
+		return nil, fmt.Errorf("%w: %d rows reference missing rows", ErrInvalidBackup, violations)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// backupValue converts a decoded JSON value back to a column value
+func backupValue(v interface{}) interface{} {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	f, _ := n.Float64()
+	return f
+}
+
+// Restore replaces the whole database with a backup archive, atomically. A
+// backup from an older schema is loaded into a scratch database at its own
+// version and migrated first, so data migrations run as they would have.
+// Archives from a newer schema are refused.
+func (s *Store) Restore(archive []byte) (*RestoreResult, error) {
+	manifest, tables, err := readBackup(archive)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.SchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	if manifest.SchemaVersion > current || manifest.SchemaVersion < 0 {
+		return nil, fmt.Errorf("%w: schema version %d is newer than this server's %d",
+			ErrInvalidBackup, manifest.SchemaVersion, current)
+	}
+
+	if manifest.SchemaVersion < current {
+		if tables, err = upgradeTables(tables, manifest.SchemaVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	counts, err := s.replaceTables(tables)
+	if err != nil {
+		return nil, err
+	}
+	return &RestoreResult{SchemaVersion: manifest.SchemaVersion, Tables: counts}, nil
+}
+
+// upgradeTables loads tables into an in-memory database at version, runs
+// the remaining migrations and reads the tables back
+func upgradeTables(tables map[string]*tableData, version int) (map[string]*tableData, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	scratch := &Store{db: db}
+	defer scratch.Close()
+
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		return nil, err
+	}
+	if err := scratch.migrateTo(version); err != nil {
+		return nil, err
+	}
+	if _, err := scratch.replaceTables(tables); err != nil {
+		return nil, err
+	}
+	if err := scratch.migrate(); err != nil {
+		return nil, fmt.Errorf("failed to upgrade backup: %w", err)
+	}
+	return dumpTables(db)
+}
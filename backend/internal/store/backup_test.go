@@ -0,0 +1,211 @@
+package store
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// backupArchive backs up s and returns the archive
+func backupArchive(t *testing.T, s *Store) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := s.Backup(&buf); err != nil {
+		t.Fatalf("Failed to back up: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// rewriteArchive copies an archive, passing each file through edit
+func rewriteArchive(t *testing.T, archive []byte, edit func(name string, data []byte) []byte) []byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("Failed to open archive: %v", err)
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		w, _ := zw.Create(f.Name)
+		w.Write(edit(f.Name, data))
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+// TestBackupRestore verifies a backup restores everything, including goals,
+// settings, notes and loans, and replaces what was there
+func TestBackupRestore(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	bookID, _ := s.CreateBook(&Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read",
+		DateRead: "2025/01/15", Rating: 5, SeriesPosition: 1.5, PurchasePrice: 12.99})
+	s.CreateNote(&Note{BookID: bookID, Kind: NoteKindNote, Text: "Dana travels back in time"})
+	s.CreateLoan(&Loan{BookID: bookID, Borrower: "Sam", LentDate: "2025/01/20"})
+	s.SetGoal(2025, 40)
+	s.SetSetting("theme", "dark")
+	archive := backupArchive(t, s)
+
+	target := setupTestStore(t)
+	defer target.Close()
+	target.CreateBook(&Book{Title: "Dune", Author: "Frank Herbert", Shelf: "read"})
+	target.SetGoal(2024, 10)
+
+	result, err := target.Restore(archive)
+	if err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+	if result.Tables["books"] != 1 || result.Tables["goals"] != 1 {
+		t.Errorf("Expected 1 book and 1 goal restored, got %v", result.Tables)
+	}
+
+	all, _ := target.GetAllBooks()
+	if len(all) != 1 || all[0].ID != bookID || all[0].Title != "Kindred" || all[0].Rating != 5 ||
+		all[0].SeriesPosition != 1.5 || all[0].PurchasePrice != 12.99 {
+		t.Fatalf("Expected only Kindred, got %+v", all)
+	}
+	if all[0].CreatedAt.IsZero() {
+		t.Error("Expected created_at restored")
+	}
+	if goal, _ := target.GetGoal(2025); goal == nil || goal.BookTarget != 40 {
+		t.Errorf("Expected 2025 goal of 40, got %+v", goal)
+	}
+	if goal, _ := target.GetGoal(2024); goal != nil {
+		t.Errorf("Expected 2024 goal replaced, got %+v", goal)
+	}
+	if theme, _ := target.GetSetting("theme"); theme != "dark" {
+		t.Errorf("Expected theme setting, got %q", theme)
+	}
+	if loans, _ := target.GetLoans(true); len(loans) != 1 || loans[0].Borrower != "Sam" {
+		t.Errorf("Expected loan to Sam, got %+v", loans)
+	}
+	if results, _ := target.Search("travels", 10); len(results) != 1 || results[0].BookID != bookID {
+		t.Errorf("Expected search index rebuilt from notes, got %+v", results)
+	}
+	if results, _ := target.Search("dune", 10); len(results) != 0 {
+		t.Errorf("Expected replaced book gone from search, got %+v", results)
+	}
+
+	// A second backup of the restored database restores the same rows
+	again, err := s.Restore(backupArchive(t, target))
+	if err != nil || again.Tables["notes"] != 1 {
+		t.Errorf("Expected round trip to restore 1 note, got %v, %v", again, err)
+	}
+}
+
+// TestRestoreInvalid verifies damaged archives are refused and change nothing
+func TestRestoreInvalid(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	s.CreateBook(&Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+	archive := backupArchive(t, s)
+
+	target := setupTestStore(t)
+	defer target.Close()
+	target.CreateBook(&Book{Title: "Dune", Author: "Frank Herbert", Shelf: "read"})
+
+	tests := []struct {
+		name    string
+		archive []byte
+	}{
+		{"not a zip", []byte("hello")},
+		{"tampered table", rewriteArchive(t, archive, func(name string, data []byte) []byte {
+			if name == "tables/books.json" {
+				return bytes.Replace(data, []byte("Kindred"), []byte("Kindling"), 1)
+			}
+			return data
+		})},
+		{"newer schema", rewriteArchive(t, archive, func(name string, data []byte) []byte {
+			if name == "manifest.json" {
+				return bytes.Replace(data, []byte(fmt.Sprintf(`"schemaVersion": %d`, len(migrations))), []byte(`"schemaVersion": 99`), 1)
+			}
+			return data
+		})},
+		{"wrong format", rewriteArchive(t, archive, func(name string, data []byte) []byte {
+			if name == "manifest.json" {
+				return bytes.Replace(data, []byte(BackupFormat), []byte("something-else"), 1)
+			}
+			return data
+		})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := target.Restore(tt.archive); !errors.Is(err, ErrInvalidBackup) {
+				t.Errorf("Expected ErrInvalidBackup, got %v", err)
+			}
+			all, _ := target.GetAllBooks()
+			if len(all) != 1 || all[0].Title != "Dune" {
+				t.Errorf("Expected database unchanged, got %+v", all)
+			}
+		})
+	}
+}
+
+// TestRestoreDanglingReference verifies a backup whose rows reference a
+// missing book is rolled back
+func TestRestoreDanglingReference(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+	bookID, _ := s.CreateBook(&Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+	s.CreateNote(&Note{BookID: bookID, Kind: NoteKindNote, Text: "A note"})
+	archive := backupArchive(t, s)
+
+	_, tables, err := readBackup(archive)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+	tables["books"].Rows = nil
+	if _, err := s.replaceTables(tables); !errors.Is(err, ErrInvalidBackup) {
+		t.Errorf("Expected ErrInvalidBackup for dangling note, got %v", err)
+	}
+	if notes, _ := s.GetNotes(bookID); len(notes) != 1 {
+		t.Errorf("Expected note kept, got %d", len(notes))
+	}
+}
+
+// TestRestoreOlderSchema verifies a backup taken before later migrations is
+// upgraded as it is restored
+func TestRestoreOlderSchema(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	old := &Store{db: db}
+	defer old.Close()
+	if err := old.migrateTo(2); err != nil {
+		t.Fatalf("Failed to create old schema: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO books (title, author, date_read, shelf) VALUES ('Kindred', 'Octavia E. Butler', '2025/01/15', 'read')`); err != nil {
+		t.Fatalf("Failed to insert book: %v", err)
+	}
+	archive := backupArchive(t, old)
+
+	s := setupTestStore(t)
+	defer s.Close()
+	result, err := s.Restore(archive)
+	if err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+	if result.SchemaVersion != 2 {
+		t.Errorf("Expected backup schema version 2, got %d", result.SchemaVersion)
+	}
+	all, _ := s.GetAllBooks()
+	if len(all) != 1 || all[0].Title != "Kindred" || all[0].Format != "" || all[0].Rating != 0 {
+		t.Fatalf("Expected Kindred with new columns defaulted, got %+v", all)
+	}
+	if reads, _ := s.GetReads(all[0].ID); len(reads) != 1 || reads[0].DateFinished != "2025/01/15" {
+		t.Errorf("Expected read created by the reads migration, got %+v", reads)
+	}
+	if results, _ := s.Search("kindred", 10); len(results) != 1 {
+		t.Errorf("Expected upgraded book indexed for search, got %+v", results)
+	}
+}
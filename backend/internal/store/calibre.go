@@ -0,0 +1,189 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// CalibreImporter reads a Calibre library's metadata.db. Every book is
+// added to-read and owned as an ebook.
+type CalibreImporter struct{}
+
+// Source names Calibre
+func (CalibreImporter) Source() string { return "calibre" }
+
+// Parse copies the database to a temporary file, since SQLite needs a path,
+// and reads it from there
+func (c CalibreImporter) Parse(r io.Reader) ([]ImportedBook, error) {
+	tmp, err := os.CreateTemp("", "calibre-*.db")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	return c.ParseFile(tmp.Name())
+}
+
+// ParseFile reads a metadata.db in place, without modifying it
+func (CalibreImporter) ParseFile(path string) ([]ImportedBook, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT id, title, COALESCE(timestamp, ''), COALESCE(pubdate, ''), series_index FROM books ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("not a Calibre library: %w", err)
+	}
+	type calibreBook struct {
+		id                int64
+		title, added, pub string
+		seriesIndex       float64
+	}
+	var found []calibreBook
+	for rows.Next() {
+		var cb calibreBook
+		var seriesIndex sql.NullFloat64
+		if err := rows.Scan(&cb.id, &cb.title, &cb.added, &cb.pub, &seriesIndex); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		cb.seriesIndex = seriesIndex.Float64
+		found = append(found, cb)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	authors, err := calibreValues(db, `
+		SELECT l.book, a.name FROM books_authors_link l JOIN authors a ON a.id = l.author ORDER BY l.id`)
+	if err != nil {
+		return nil, err
+	}
+	series, err := calibreValues(db, `
+		SELECT l.book, s.name FROM books_series_link l JOIN series s ON s.id = l.series`)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := calibreValues(db, `
+		SELECT l.book, t.name FROM books_tags_link l JOIN tags t ON t.id = l.tag ORDER BY l.id`)
+	if err != nil {
+		return nil, err
+	}
+	publishers, err := calibreValues(db, `
+		SELECT l.book, p.name FROM books_publishers_link l JOIN publishers p ON p.id = l.publisher`)
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := calibreValues(db, `
+		SELECT l.book, r.rating FROM books_ratings_link l JOIN ratings r ON r.id = l.rating`)
+	if err != nil {
+		return nil, err
+	}
+	isbns, err := calibreValues(db, `
+		SELECT book, val FROM identifiers WHERE lower(type) = 'isbn'`)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := calibrePages(db)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ImportedBook, 0, len(found))
+	for _, cb := range found {
+		b := Book{
+			Title:       cb.title,
+			DateAdded:   importDate(cb.added),
+			Shelf:       "to-read",
+			Format:      books.FormatEbook,
+			Ownership:   "owned",
+			OwnedFormat: books.FormatEbook,
+			Tags:        joinTags(strings.Join(tags[cb.id], ",")),
+		}
+		if names := authors[cb.id]; len(names) > 0 {
+			b.Author = names[0]
+			b.AdditionalAuthors = strings.Join(names[1:], ", ")
+		}
+		if s := series[cb.id]; len(s) > 0 {
+			b.Series = s[0]
+			b.SeriesPosition = cb.seriesIndex
+		}
+		if p := publishers[cb.id]; len(p) > 0 {
+			b.Publisher = p[0]
+		}
+		// Calibre marks an unknown publication date as the year 101
+		if year, err := strconv.Atoi(strings.SplitN(cb.pub, "-", 2)[0]); err == nil && year >= 1000 {
+			b.YearPublished = year
+		}
+		if r := ratings[cb.id]; len(r) > 0 {
+			// Ratings are stored out of 10, in half stars
+			if n, err := strconv.Atoi(r[0]); err == nil {
+				b.Rating = min(float64(n)/2, 5)
+			}
+		}
+		b.ISBN, b.ISBN13 = splitISBNs(isbns[cb.id]...)
+		if p := pages[cb.id]; len(p) > 0 {
+			b.Pages = int(importNumber(p[0]))
+		}
+		result = append(result, ImportedBook{Book: b})
+	}
+	return result, nil
+}
+
+// calibreValues runs a query returning (book, value) pairs and groups the
+// values by book
+func calibreValues(db *sql.DB, query string) (map[int64][]string, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[int64][]string)
+	for rows.Next() {
+		var book int64
+		var value sql.NullString
+		if err := rows.Scan(&book, &value); err != nil {
+			return nil, err
+		}
+		if value.Valid && strings.TrimSpace(value.String) != "" {
+			values[book] = append(values[book], strings.TrimSpace(value.String))
+		}
+	}
+	return values, rows.Err()
+}
+
+// calibrePages reads the page-count custom column, if the library has one,
+// such as the #pages column of the Count Pages plugin
+func calibrePages(db *sql.DB) (map[int64][]string, error) {
+	var id int64
+	err := db.QueryRow(`
+		SELECT id FROM custom_columns
+		WHERE datatype IN ('int', 'float')
+		  AND (lower(label) IN ('pages', 'page_count', 'pagecount', 'count_pages') OR lower(name) = 'pages')
+		ORDER BY id LIMIT 1
+	`).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Simple custom columns keep one value per book in custom_column_N
+	return calibreValues(db, fmt.Sprintf("SELECT book, value FROM custom_column_%d", id))
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// calibreSchema is the part of a Calibre metadata.db the importer reads
+const calibreSchema = `
+CREATE TABLE books (id INTEGER PRIMARY KEY, title TEXT, sort TEXT, timestamp TIMESTAMP, pubdate TIMESTAMP,
+                    series_index REAL NOT NULL DEFAULT 1.0, author_sort TEXT, isbn TEXT DEFAULT "", path TEXT);
+CREATE TABLE authors (id INTEGER PRIMARY KEY, name TEXT, sort TEXT);
+CREATE TABLE books_authors_link (id INTEGER PRIMARY KEY, book INTEGER, author INTEGER);
+CREATE TABLE series (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE books_series_link (id INTEGER PRIMARY KEY, book INTEGER, series INTEGER);
+CREATE TABLE tags (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE books_tags_link (id INTEGER PRIMARY KEY, book INTEGER, tag INTEGER);
+CREATE TABLE publishers (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE books_publishers_link (id INTEGER PRIMARY KEY, book INTEGER, publisher INTEGER);
+CREATE TABLE ratings (id INTEGER PRIMARY KEY, rating INTEGER);
+CREATE TABLE books_ratings_link (id INTEGER PRIMARY KEY, book INTEGER, rating INTEGER);
+CREATE TABLE identifiers (id INTEGER PRIMARY KEY, book INTEGER, type TEXT, val TEXT);
+CREATE TABLE custom_columns (id INTEGER PRIMARY KEY, label TEXT, name TEXT, datatype TEXT);
+CREATE TABLE custom_column_3 (id INTEGER PRIMARY KEY, book INTEGER, value INTEGER);
+
+INSERT INTO books (id, title, timestamp, pubdate, series_index) VALUES
+	(1, 'A Wizard of Earthsea', '2023-01-02 10:00:00+00:00', '1968-11-01 00:00:00+00:00', 1),
+	(2, 'The Tombs of Atuan', '2023-01-03 10:00:00+00:00', '0101-01-01 00:00:00+00:00', 2),
+	(3, 'Earthsea (duplicate copy)', '2023-01-04 10:00:00+00:00', '', 1);
+INSERT INTO authors VALUES (1, 'Ursula K. Le Guin', 'Le Guin, Ursula K.'), (2, 'Charles Vess', 'Vess, Charles');
+INSERT INTO books_authors_link (book, author) VALUES (1, 1), (1, 2), (2, 1), (3, 1);
+INSERT INTO series VALUES (1, 'Earthsea Cycle');
+INSERT INTO books_series_link (book, series) VALUES (1, 1), (2, 1);
+INSERT INTO tags VALUES (1, 'Fantasy'), (2, 'Classics');
+INSERT INTO books_tags_link (book, tag) VALUES (1, 1), (1, 2);
+INSERT INTO publishers VALUES (1, 'Parnassus Press');
+INSERT INTO books_publishers_link (book, publisher) VALUES (1, 1);
+INSERT INTO ratings VALUES (1, 9);
+INSERT INTO books_ratings_link (book, rating) VALUES (1, 1);
+INSERT INTO identifiers (book, type, val) VALUES (1, 'isbn', '9780547773742'), (1, 'goodreads', '13642'), (3, 'isbn', '9780547773742');
+INSERT INTO custom_columns VALUES (3, 'pages', 'Pages', 'int');
+INSERT INTO custom_column_3 (book, value) VALUES (1, 183);
+`
+
+// writeCalibreLibrary creates a metadata.db in a temporary directory
+func writeCalibreLibrary(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "metadata.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("Failed to create library: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(calibreSchema); err != nil {
+		t.Fatalf("Failed to create library: %v", err)
+	}
+	return path
+}
+
+// TestCalibreParseFile verifies metadata, series, tags, identifiers and pages
+func TestCalibreParseFile(t *testing.T) {
+	parsed, err := CalibreImporter{}.ParseFile(writeCalibreLibrary(t))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if len(parsed) != 3 {
+		t.Fatalf("Expected 3 books, got %d", len(parsed))
+	}
+
+	b := parsed[0].Book
+	if b.Title != "A Wizard of Earthsea" || b.Author != "Ursula K. Le Guin" || b.AdditionalAuthors != "Charles Vess" {
+		t.Errorf("Unexpected title or authors: %+v", b)
+	}
+	if b.Series != "Earthsea Cycle" || b.SeriesPosition != 1 || b.Publisher != "Parnassus Press" || b.YearPublished != 1968 {
+		t.Errorf("Unexpected series, publisher or year: %+v", b)
+	}
+	if b.ISBN13 != "9780547773742" || b.Pages != 183 || b.Rating != 4.5 || b.Tags != "fantasy, classics" {
+		t.Errorf("Unexpected identifiers, pages, rating or tags: %+v", b)
+	}
+	if b.Shelf != "to-read" || b.Ownership != "owned" || b.Format != "ebook" || b.DateAdded != "2023/01/02" {
+		t.Errorf("Unexpected status: %+v", b)
+	}
+	if parsed[1].Book.YearPublished != 0 {
+		t.Errorf("Expected Calibre's undefined date to be ignored, got %d", parsed[1].Book.YearPublished)
+	}
+}
+
+// TestCalibreImportDedupesByISBN verifies an uploaded library is imported
+// once per ISBN
+func TestCalibreImportDedupesByISBN(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	f, err := os.Open(writeCalibreLibrary(t))
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	defer f.Close()
+
+	result, err := s.ImportBooks(CalibreImporter{}, f)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Imported != 2 || result.Duplicates != 1 {
+		t.Errorf("Expected 2 imported and 1 duplicate, got %+v", result)
+	}
+}
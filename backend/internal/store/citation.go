@@ -0,0 +1,72 @@
+package store
+
+import (
+	"database/sql"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// CitationYear returns the year cited for an edition: its own, or the
+// original when the edition's is unknown
+func (b *Book) CitationYear() int {
+	if b.YearPublished > 0 {
+		return b.YearPublished
+	}
+	return b.OriginalPublicationYear
+}
+
+// migrateCitationKeys gives every book a citation key, assigned in ID order
+// so the oldest of books sharing a key keeps the plain one
+func migrateCitationKeys(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		ALTER TABLE books ADD COLUMN citation_key TEXT DEFAULT '';
+		CREATE UNIQUE INDEX idx_books_citation_key ON books(citation_key) WHERE citation_key != '';
+	`); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query("SELECT id, author, title, year_published, original_publication_year FROM books ORDER BY id")
+	if err != nil {
+		return err
+	}
+	var all []Book
+	for rows.Next() {
+		var b Book
+		if err := rows.Scan(&b.ID, &b.Author, &b.Title, &b.YearPublished, &b.OriginalPublicationYear); err != nil {
+			rows.Close()
+			return err
+		}
+		all = append(all, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, b := range all {
+		if _, err := assignCitationKey(tx, &b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignCitationKey stores a new book's citation key. A key taken by another
+// book gets the first free suffix. Keys never change afterwards, so that
+// edits and deletions don't break documents citing them.
+func assignCitationKey(q querier, b *Book) (string, error) {
+	base := books.CitationKey(b.Author, b.Title, b.CitationYear())
+	key := base
+	for n := 1; ; n++ {
+		var taken int
+		if err := q.QueryRow("SELECT COUNT(*) FROM books WHERE citation_key = ?", key).Scan(&taken); err != nil {
+			return "", err
+		}
+		if taken == 0 {
+			break
+		}
+		key = base + books.CitationSuffix(n)
+	}
+	_, err := q.Exec("UPDATE books SET citation_key = ? WHERE id = ?", key, b.ID)
+	return key, err
+}
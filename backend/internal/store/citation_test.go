@@ -0,0 +1,29 @@
+package store
+
+import "testing"
+
+// TestCitationKeysStable verifies keys survive edits and the deletion of a
+// book sharing the key
+func TestCitationKeysStable(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	first, _ := s.CreateBook(&Book{Title: "Foo", Author: "Jane Smith", YearPublished: 2020, Shelf: "read"})
+	second, _ := s.CreateBook(&Book{Title: "Foo", Author: "Jane Smith", YearPublished: 2020, Shelf: "to-read"})
+
+	book, _ := s.GetBook(second)
+	if book.CitationKey != "smith2020foob" {
+		t.Fatalf("Expected smith2020foob, got %q", book.CitationKey)
+	}
+
+	if err := s.DeleteBook(first); err != nil {
+		t.Fatalf("Failed to delete book: %v", err)
+	}
+	book.Title = "Foo, Revised"
+	if err := s.UpdateBook(book); err != nil {
+		t.Fatalf("Failed to update book: %v", err)
+	}
+	if book, _ = s.GetBook(second); book.CitationKey != "smith2020foob" {
+		t.Errorf("Expected the key kept after edits and deletions, got %q", book.CitationKey)
+	}
+}
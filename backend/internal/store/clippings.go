@@ -0,0 +1,102 @@
+package store
+
+import (
+	"time"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// ClippingsResult summarises a Kindle clippings import
+type ClippingsResult struct {
+	Imported   int      // highlights and notes stored
+	Duplicates int      // clippings already stored on their book
+	Bookmarks  int      // bookmarks, which carry no text and are not stored
+	Unmatched  []string // "Title (Author)" of clippings with no matching book
+}
+
+// ImportClippings stores Kindle highlights and notes on the books they came
+// from. Books are matched by normalized title, using the author to choose
+// between books sharing a title. Re-importing the same file adds nothing.
+func (s *Store) ImportClippings(clippings []books.Clipping) (*ClippingsResult, error) {
+	all, err := s.GetAllBooks()
+	if err != nil {
+		return nil, err
+	}
+	byTitle := make(map[string][]Book)
+	for _, b := range all {
+		key := books.NormalizeTitle(b.Title)
+		byTitle[key] = append(byTitle[key], b)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &ClippingsResult{}
+	unmatched := make(map[string]bool)
+	for _, c := range clippings {
+		if c.Kind == books.ClippingBookmark {
+			result.Bookmarks++
+			continue
+		}
+
+		book := matchClipping(byTitle[books.NormalizeTitle(c.Title)], c.Author)
+		if book == nil {
+			name := c.Title
+			if c.Author != "" {
+				name += " (" + c.Author + ")"
+			}
+			if !unmatched[name] {
+				unmatched[name] = true
+				result.Unmatched = append(result.Unmatched, name)
+			}
+			continue
+		}
+
+		kind := NoteKindHighlight
+		if c.Kind == books.ClippingNote {
+			kind = NoteKindNote
+		}
+
+		var exists int
+		if err := tx.QueryRow(`
+			SELECT COUNT(*) FROM notes WHERE book_id = ? AND kind = ? AND location = ? AND text = ?
+		`, book.ID, kind, c.Location, c.Text).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if exists > 0 {
+			result.Duplicates++
+			continue
+		}
+
+		createdAt := c.AddedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO notes (book_id, kind, text, page, location, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, book.ID, kind, c.Text, c.Page, c.Location, createdAt, createdAt); err != nil {
+			return nil, err
+		}
+		result.Imported++
+	}
+
+	return result, tx.Commit()
+}
+
+// matchClipping picks the book a clipping belongs to from the books sharing
+// its title. A lone candidate matches; otherwise the author decides.
+func matchClipping(candidates []Book, author string) *Book {
+	if len(candidates) == 1 {
+		return &candidates[0]
+	}
+	for i, b := range candidates {
+		if books.SameAuthor(b.Author, author) {
+			return &candidates[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// TestImportClippings verifies clippings land on matching books once
+func TestImportClippings(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	id, _ := s.CreateBook(&Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+	clippings := []books.Clipping{
+		{Title: "Kindred", Author: "Octavia Butler", Kind: books.ClippingHighlight, Location: "100-102", Text: "The ache"},
+		{Title: "Kindred", Author: "Octavia Butler", Kind: books.ClippingNote, Location: "102", Text: "Compare with Dawn"},
+		{Title: "Kindred", Author: "Octavia Butler", Kind: books.ClippingBookmark, Location: "300"},
+		{Title: "Parable of the Sower", Author: "Octavia Butler", Kind: books.ClippingHighlight, Location: "5", Text: "God is change"},
+	}
+
+	result, err := s.ImportClippings(clippings)
+	if err != nil {
+		t.Fatalf("ImportClippings failed: %v", err)
+	}
+	if result.Imported != 2 || result.Bookmarks != 1 {
+		t.Errorf("Expected 2 imported and 1 bookmark, got %+v", result)
+	}
+	if len(result.Unmatched) != 1 || result.Unmatched[0] != "Parable of the Sower (Octavia Butler)" {
+		t.Errorf("Expected Parable unmatched, got %v", result.Unmatched)
+	}
+
+	notes, _ := s.GetNotes(id)
+	if len(notes) != 2 || notes[0].Kind != NoteKindHighlight || notes[1].Kind != NoteKindNote {
+		t.Errorf("Expected a highlight and a note, got %+v", notes)
+	}
+
+	// Importing again stores nothing new
+	result, err = s.ImportClippings(clippings)
+	if err != nil {
+		t.Fatalf("ImportClippings failed: %v", err)
+	}
+	if result.Imported != 0 || result.Duplicates != 2 {
+		t.Errorf("Expected 2 duplicates on re-import, got %+v", result)
+	}
+}
+
+// TestImportClippingsSameTitle verifies the author picks between books sharing a title
+func TestImportClippingsSameTitle(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	s.CreateBook(&Book{Title: "Dawn", Author: "Octavia E. Butler", Shelf: "read"})
+	elie, _ := s.CreateBook(&Book{Title: "Dawn", Author: "Elie Wiesel", Shelf: "read"})
+
+	_, err := s.ImportClippings([]books.Clipping{
+		{Title: "Dawn", Author: "Elie Wiesel", Kind: books.ClippingHighlight, Location: "10", Text: "Night was falling"},
+	})
+	if err != nil {
+		t.Fatalf("ImportClippings failed: %v", err)
+	}
+	notes, _ := s.GetNotes(elie)
+	if len(notes) != 1 {
+		t.Errorf("Expected the highlight on Wiesel's Dawn, got %d notes", len(notes))
+	}
+}
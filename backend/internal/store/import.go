@@ -3,6 +3,7 @@ package store
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/kristenwomack/reading-app/backend/internal/books"
@@ -31,18 +32,56 @@ func (s *Store) ImportFromJSON(jsonBooks []books.Book) (int, error) {
 			Pages:                   jb.GetPages(),
 			YearPublished:           toInt(jb.YearPublished),
 			OriginalPublicationYear: toInt(jb.OriginalPublicationYear),
+			DateStarted:             jb.DateStarted,
 			DateRead:                jb.DateRead,
 			DateAdded:               toString(jb.DateAdded),
 			Shelf:                   jb.Shelf,
 			Review:                  toString(jb.MyReview),
 			CoverURL:                buildCoverURL(jb.ISBN, jb.ISBN13),
+			Format:                  jb.GetFormat(),
+			DurationMinutes:         jb.GetDurationMinutes(),
+			DNFDate:                 jb.DNFDate,
+			DNFPage:                 jb.GetDNFPage(),
+			DNFPercent:              jb.GetDNFPercent(),
+			DNFReason:               jb.DNFReason,
+			Ownership:               jb.GetOwnership(),
+			OwnedFormat:             books.NormalizeFormat(jb.OwnedFormat),
+			PurchaseDate:            jb.PurchaseDate,
+			PurchasePrice:           jb.GetPurchasePrice(),
+			ShelfLocation:           jb.ShelfLocation,
+			Tags:                    strings.Join(jb.GetTags(), ", "),
+			Rating:                  jb.GetRating(),
+		}
+
+		// Goodreads keeps did-not-finish as a custom shelf name
+		if books.NormalizeShelf(b.Shelf) == books.ShelfDNF || books.HasDNFShelf(jb.Bookshelves) {
+			b.Shelf = books.ShelfDNF
 		}
 
 		if b.Title == "" || b.Author == "" {
 			continue // Skip invalid entries
 		}
 
-		if _, err := s.CreateBook(&b); err != nil {
+		var notes []Note
+		for _, jn := range jb.Notes {
+			note := Note{Kind: jn.Kind, Text: jn.Text, Page: jn.Page, Location: jn.Location}
+			if note.Kind == "" {
+				note.Kind = NoteKindNote
+			}
+			notes = append(notes, note)
+		}
+
+		// Goodreads only keeps the latest date, so earlier re-reads are undated
+		recorded := 0
+		if b.DateRead != "" {
+			recorded = 1
+		}
+		var reads []Read
+		for i := recorded; i < toInt(jb.GoodreadsReadCount); i++ {
+			reads = append(reads, Read{})
+		}
+
+		if _, err := s.createBookWith(&b, reads, notes); err != nil {
 			return imported, fmt.Errorf("failed to import book %q: %w", b.Title, err)
 		}
 		imported++
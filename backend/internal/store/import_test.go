@@ -0,0 +1,68 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// TestImportDNFShelf verifies Goodreads did-not-finish shelves import as dnf
+func TestImportDNFShelf(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	_, err := s.ImportFromJSON([]books.Book{
+		{Title: "Exclusive", Author: "A", Shelf: "did-not-finish"},
+		{Title: "Tagged", Author: "B", Shelf: "to-read", Bookshelves: "to-read, abandoned"},
+		{Title: "Plain", Author: "C", Shelf: "to-read"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+
+	all, _ := s.GetAllBooks()
+	shelves := make(map[string]string)
+	for _, b := range all {
+		shelves[b.Title] = b.Shelf
+	}
+	if shelves["Exclusive"] != "dnf" || shelves["Tagged"] != "dnf" || shelves["Plain"] != "to-read" {
+		t.Errorf("Unexpected shelves after import: %v", shelves)
+	}
+}
+
+// TestImportTags verifies non-exclusive Goodreads shelves become tags
+func TestImportTags(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	_, err := s.ImportFromJSON([]books.Book{
+		{Title: "Piranesi", Author: "Susanna Clarke", Shelf: "read", DateRead: "2024/01/01", Bookshelves: "read, favorites, fantasy"},
+	})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	all, _ := s.GetAllBooks()
+	if len(all) != 1 || all[0].Tags != "favorites, fantasy" {
+		t.Errorf("Expected tags \"favorites, fantasy\", got %+v", all)
+	}
+}
+
+// TestImportAtomicPerBook verifies a book whose reads fail to import is not
+// left behind without them
+func TestImportAtomicPerBook(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	if _, err := s.db.Exec("DROP TABLE reads"); err != nil {
+		t.Fatalf("Failed to drop reads: %v", err)
+	}
+	_, err := s.ImportFromJSON([]books.Book{
+		{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read", GoodreadsReadCount: float64(2)},
+	})
+	if err == nil {
+		t.Fatal("Expected the import to fail")
+	}
+	if count, _ := s.BookCount(); count != 0 {
+		t.Errorf("Expected no book left half-imported, got %d", count)
+	}
+}
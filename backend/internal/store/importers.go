@@ -0,0 +1,282 @@
+package store
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// ImportedBook is a book parsed from another app's export. Book holds the
+// latest finished read in DateRead; Reads holds the others.
+type ImportedBook struct {
+	Book  Book
+	Reads []Read
+}
+
+// Importer parses a library export from another reading app
+type Importer interface {
+	// Source names the app the export comes from
+	Source() string
+	Parse(r io.Reader) ([]ImportedBook, error)
+}
+
+// Importers are the available importers by source name
+var Importers = map[string]Importer{
+	"storygraph":   StoryGraphImporter{},
+	"librarything": LibraryThingImporter{},
+	"calibre":      CalibreImporter{},
+}
+
+// ImportResult summarises an import into an existing library
+type ImportResult struct {
+	Imported   int // books added
+	Duplicates int // books already in the library, matched by ISBN or title and author
+	Skipped    int // entries without a title or author
+}
+
+// ImportBooks adds books parsed by an importer to the library. Unlike
+// ImportFromJSON it runs against a non-empty database, skipping books that
+// are already present.
+func (s *Store) ImportBooks(imp Importer, r io.Reader) (*ImportResult, error) {
+	parsed, err := imp.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s export: %w", imp.Source(), err)
+	}
+	return s.ImportParsed(parsed)
+}
+
+// ImportParsed stores books already parsed by an importer with their reads,
+// skipping duplicates. Each book is added with its reads in one transaction.
+func (s *Store) ImportParsed(parsed []ImportedBook) (*ImportResult, error) {
+	existing, err := s.GetAllBooks()
+	if err != nil {
+		return nil, err
+	}
+	seen := newDedupe()
+	for _, b := range existing {
+		seen.add(b)
+	}
+
+	result := &ImportResult{}
+	for _, ib := range parsed {
+		b := ib.Book
+		if b.Title == "" || b.Author == "" {
+			result.Skipped++
+			continue
+		}
+		duplicate := seen.has(b)
+		seen.add(b)
+		if duplicate {
+			result.Duplicates++
+			continue
+		}
+
+		if b.CoverURL == "" {
+			b.CoverURL = buildCoverURL(b.ISBN, b.ISBN13)
+		}
+		if _, err := s.createBookWith(&b, ib.Reads, nil); err != nil {
+			return result, fmt.Errorf("failed to import book %q: %w", b.Title, err)
+		}
+		result.Imported++
+	}
+	return result, nil
+}
+
+// dedupe remembers books by each of their ISBNs and by normalized title
+// and author, to spot one already present
+type dedupe struct {
+	isbns  map[string]bool
+	titles map[string][]string // normalized title to authors
+}
+
+func newDedupe() *dedupe {
+	return &dedupe{isbns: make(map[string]bool), titles: make(map[string][]string)}
+}
+
+// add records a book
+func (d *dedupe) add(b Book) {
+	for _, isbn := range []string{b.ISBN, b.ISBN13} {
+		if isbn != "" {
+			d.isbns[isbn] = true
+		}
+	}
+	title := books.NormalizeTitle(b.Title)
+	d.titles[title] = append(d.titles[title], b.Author)
+}
+
+// has reports whether a book shares an ISBN with one recorded, or its
+// title and author (see books.SameAuthor)
+func (d *dedupe) has(b Book) bool {
+	for _, isbn := range []string{b.ISBN, b.ISBN13} {
+		if isbn != "" && d.isbns[isbn] {
+			return true
+		}
+	}
+	for _, author := range d.titles[books.NormalizeTitle(b.Title)] {
+		if books.SameAuthor(author, b.Author) {
+			return true
+		}
+	}
+	return false
+}
+
+// readTable reads a delimited file with a header row into one map per row,
+// keyed by column name. A UTF-8 or UTF-16 byte order mark is honoured.
+func readTable(r io.Reader, comma rune) ([]map[string]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = decodeBOM(data)
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.Comma = comma
+	cr.LazyQuotes = true
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("missing header row")
+	}
+
+	header := records[0]
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, rec := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(rec) {
+				row[strings.TrimSpace(name)] = strings.TrimSpace(rec[i])
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// decodeBOM strips a UTF-8 byte order mark and converts UTF-16 to UTF-8
+func decodeBOM(data []byte) []byte {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return data[3:]
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}), bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		bigEndian := data[0] == 0xFE
+		units := make([]uint16, 0, len(data)/2)
+		for i := 2; i+1 < len(data); i += 2 {
+			if bigEndian {
+				units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+			} else {
+				units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+			}
+		}
+		return []byte(string(utf16.Decode(units)))
+	default:
+		return data
+	}
+}
+
+// importDate converts a YYYY-MM-DD or YYYY/MM/DD date, possibly with a
+// time (including RFC 3339's) or without a day, to our zero-padded
+// YYYY/MM/DD. Unparseable dates become "".
+func importDate(s string) string {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == 'T' })
+	if len(fields) == 0 {
+		return ""
+	}
+	parts := strings.FieldsFunc(fields[0], func(r rune) bool { return r == '-' || r == '/' })
+	if len(parts) == 0 || len(parts) > 3 {
+		return ""
+	}
+	nums := make([]string, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return ""
+		}
+		if i == 0 {
+			nums[i] = fmt.Sprintf("%04d", n)
+		} else {
+			nums[i] = fmt.Sprintf("%02d", n)
+		}
+	}
+	date := strings.Join(nums, "/")
+	if _, err := books.ParseDate(date); err != nil {
+		return ""
+	}
+	return date
+}
+
+// splitISBNs picks the first ISBN-10 and ISBN-13 out of values such as
+// "[0807083690]" or "0807083690, 9780807083697". Other identifiers are
+// ignored.
+func splitISBNs(values ...string) (isbn, isbn13 string) {
+	for _, v := range values {
+		for _, field := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' || r == ';' }) {
+			id := strings.ToUpper(strings.Trim(strings.ReplaceAll(field, "-", ""), `[]="`))
+			switch {
+			case len(id) == 13 && isDigits(id) && isbn13 == "":
+				isbn13 = id
+			case len(id) == 10 && isDigits(id[:9]) && (isDigits(id[9:]) || id[9] == 'X') && isbn == "":
+				isbn = id
+			}
+		}
+	}
+	return isbn, isbn13
+}
+
+// isDigits reports whether s is non-empty and all ASCII digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// importRating parses a possibly fractional star rating, such as
+// StoryGraph's quarter stars, to the nearest quarter star from 0 to 5
+func importRating(s string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || f < 0 {
+		return 0
+	}
+	return min(books.QuarterStars(f), 5)
+}
+
+// importNumber parses the leading number of a value such as "264 pages"
+// or "$12.99"
+func importNumber(s string) float64 {
+	s = strings.TrimLeft(strings.TrimSpace(s), "$£€ ")
+	end := 0
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.') {
+		end++
+	}
+	f, _ := strconv.ParseFloat(s[:end], 64)
+	return f
+}
+
+// joinTags normalizes a comma-separated tag list
+func joinTags(s string) string {
+	return strings.Join(books.ParseTags(s), ", ")
+}
+
+// sortReads orders reads oldest first by finish date, or start date for a
+// read still in progress
+func sortReads(reads []Read) {
+	key := func(rd Read) string {
+		if rd.DateFinished != "" {
+			return rd.DateFinished
+		}
+		return rd.DateStarted
+	}
+	sort.SliceStable(reads, func(i, j int) bool { return key(reads[i]) < key(reads[j]) })
+}
@@ -0,0 +1,241 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// LibraryThingImporter reads LibraryThing's tab-separated or JSON export
+type LibraryThingImporter struct{}
+
+// Source names LibraryThing
+func (LibraryThingImporter) Source() string { return "librarything" }
+
+// libraryThingJSONFields maps JSON export keys to the TSV column names, so
+// both formats share one mapping onto Book
+var libraryThingJSONFields = map[string]string{
+	"title":         "Title",
+	"primaryauthor": "Primary Author",
+	"publication":   "Publication",
+	"date":          "Date",
+	"review":        "Review",
+	"rating":        "Rating",
+	"format":        "Media",
+	"pages":         "Page Count",
+	"dateacquired":  "Acquired",
+	"datestarted":   "Date Started",
+	"dateread":      "Date Read",
+	"tags":          "Tags",
+	"collections":   "Collections",
+	"originalisbn":  "ISBN",
+	"isbn":          "ISBNs",
+	"entrydate":     "Entry Date",
+	"purchaseprice": "Purchase Price",
+}
+
+// fourDigits finds a year in LibraryThing's free-text "Date"
+var fourDigits = regexp.MustCompile(`\b\d{4}\b`)
+
+// Parse detects the format: the JSON export starts with an object or array
+func (LibraryThingImporter) Parse(r io.Reader) ([]ImportedBook, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = decodeBOM(data)
+
+	var rows []map[string]string
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		rows, err = libraryThingJSONRows(trimmed)
+	} else {
+		rows, err = readTable(bytes.NewReader(data), '\t')
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ImportedBook, len(rows))
+	for i, row := range rows {
+		result[i] = libraryThingBook(row)
+	}
+	return result, nil
+}
+
+// libraryThingJSONRows flattens the JSON export, an object of books keyed
+// by book ID, into rows keyed like the TSV
+func libraryThingJSONRows(data []byte) ([]map[string]string, error) {
+	var entries []map[string]interface{}
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, err
+		}
+	} else {
+		var byID map[string]map[string]interface{}
+		if err := json.Unmarshal(data, &byID); err != nil {
+			return nil, err
+		}
+		ids := make([]string, 0, len(byID))
+		for id := range byID {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			entries = append(entries, byID[id])
+		}
+	}
+
+	rows := make([]map[string]string, len(entries))
+	for i, entry := range entries {
+		row := make(map[string]string)
+		for key, column := range libraryThingJSONFields {
+			row[column] = strings.TrimSpace(flattenJSON(entry[key]))
+		}
+		// Authors after the primary one, named first-last
+		if authors, ok := entry["authors"].([]interface{}); ok && len(authors) > 1 {
+			var names []string
+			for _, a := range authors[1:] {
+				if author, ok := a.(map[string]interface{}); ok {
+					names = append(names, flattenJSON(author["fl"]))
+				}
+			}
+			row["Secondary Author"] = strings.Join(names, "|")
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// flattenJSON renders a JSON value as text: lists are comma-separated and
+// objects such as {"code": "1", "text": "Paperback"} give their text
+func flattenJSON(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			if s := flattenJSON(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case map[string]interface{}:
+		if text, ok := val["text"]; ok {
+			return flattenJSON(text)
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			if s := flattenJSON(val[k]); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// libraryThingBook maps a TSV row onto a book. Collections decide the
+// shelf and ownership: "Your library" is owned, "Wishlist" is wanted, and
+// "Currently reading" and "To read" name shelves.
+func libraryThingBook(row map[string]string) ImportedBook {
+	b := Book{
+		Title:         row["Title"],
+		Author:        firstLast(row["Primary Author"]),
+		Publisher:     libraryThingPublisher(row["Publication"]),
+		Pages:         int(importNumber(row["Page Count"])),
+		DateStarted:   importDate(row["Date Started"]),
+		DateRead:      importDate(row["Date Read"]),
+		DateAdded:     importDate(row["Entry Date"]),
+		Review:        row["Review"],
+		Format:        libraryThingFormat(row["Media"]),
+		PurchaseDate:  importDate(row["Acquired"]),
+		PurchasePrice: importNumber(row["Purchase Price"]),
+		Tags:          joinTags(row["Tags"]),
+		Rating:        importRating(row["Rating"]),
+	}
+	if year := fourDigits.FindString(row["Date"]); year != "" {
+		b.YearPublished, _ = strconv.Atoi(year)
+	}
+	var additional []string
+	for _, name := range strings.FieldsFunc(row["Secondary Author"], func(r rune) bool { return r == '|' || r == ';' }) {
+		if name = firstLast(name); name != "" {
+			additional = append(additional, name)
+		}
+	}
+	b.AdditionalAuthors = strings.Join(additional, ", ")
+	b.ISBN, b.ISBN13 = splitISBNs(row["ISBN"], row["ISBNs"])
+
+	collections := make(map[string]bool)
+	for _, c := range strings.Split(row["Collections"], ",") {
+		collections[strings.ToLower(strings.TrimSpace(c))] = true
+	}
+	switch {
+	case b.DateRead != "" || collections["read but unowned"]:
+		b.Shelf = "read"
+	case collections["currently reading"]:
+		b.Shelf = "currently-reading"
+	default:
+		b.Shelf = "to-read"
+	}
+	switch {
+	case collections["wishlist"]:
+		b.Ownership = "wishlist"
+	case collections["your library"] || row["Collections"] == "":
+		b.Ownership = "owned"
+		b.OwnedFormat = b.Format
+	}
+	return ImportedBook{Book: b}
+}
+
+// firstLast turns a "Butler, Octavia E." author into "Octavia E. Butler"
+func firstLast(name string) string {
+	name = strings.TrimSpace(name)
+	last, first, found := strings.Cut(name, ",")
+	if !found {
+		return name
+	}
+	return strings.TrimSpace(strings.TrimSpace(first) + " " + strings.TrimSpace(last))
+}
+
+// libraryThingPublisher extracts the publisher from a publication line
+// such as "Beacon Press (2004), Paperback, 264 pages"
+func libraryThingPublisher(publication string) string {
+	if i := strings.IndexAny(publication, "(,"); i >= 0 {
+		publication = publication[:i]
+	}
+	// "Boston: Beacon Press" names the place first
+	if i := strings.LastIndex(publication, ": "); i >= 0 {
+		publication = publication[i+2:]
+	}
+	return strings.TrimSpace(publication)
+}
+
+// libraryThingFormat normalizes LibraryThing media such as "Paper Book",
+// "Ebook" or "Audiobook"
+func libraryThingFormat(media string) string {
+	if f := books.NormalizeFormat(media); f != "" {
+		return f
+	}
+	if strings.Contains(strings.ToLower(media), "book") {
+		return books.FormatPrint
+	}
+	return ""
+}
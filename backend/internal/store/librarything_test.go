@@ -0,0 +1,77 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLibraryThingParseTSV verifies the tab-separated export
+func TestLibraryThingParseTSV(t *testing.T) {
+	tsv := "Book Id\tTitle\tPrimary Author\tSecondary Author\tPublication\tDate\tReview\tRating\tMedia\tPage Count\tDate Started\tDate Read\tTags\tCollections\tISBN\tISBNs\tEntry Date\tPurchase Price\n" +
+		"1\tKindred\tButler, Octavia E.\t\tBeacon Press (2004), Paperback, 264 pages\t1979\tA classic.\t4.5\tPaper Book\t264\t2024-06-01\t2024-06-20\tclassics, sci-fi\tYour library\t[0807083690]\t0807083690, 9780807083697\t2023-01-02\t$12.99\n" +
+		"2\tThe Dispossessed\tLe Guin, Ursula K.\t\t\t1974\t\t\tEbook\t\t\t\t\tWishlist\t\t\t2023-02-03\t\n"
+
+	parsed, err := LibraryThingImporter{}.Parse(strings.NewReader(tsv))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if len(parsed) != 2 {
+		t.Fatalf("Expected 2 books, got %d", len(parsed))
+	}
+
+	b := parsed[0].Book
+	if b.Author != "Octavia E. Butler" || b.Publisher != "Beacon Press" || b.YearPublished != 1979 || b.Pages != 264 {
+		t.Errorf("Unexpected book: %+v", b)
+	}
+	if b.ISBN != "0807083690" || b.ISBN13 != "9780807083697" {
+		t.Errorf("Unexpected ISBNs: %q %q", b.ISBN, b.ISBN13)
+	}
+	if b.Shelf != "read" || b.DateRead != "2024/06/20" || b.DateStarted != "2024/06/01" || b.DateAdded != "2023/01/02" {
+		t.Errorf("Unexpected shelf or dates: %+v", b)
+	}
+	if b.Rating != 4.5 || b.Format != "print" || b.Ownership != "owned" || b.PurchasePrice != 12.99 {
+		t.Errorf("Unexpected rating, format, ownership or price: %+v", b)
+	}
+
+	wish := parsed[1].Book
+	if wish.Author != "Ursula K. Le Guin" || wish.Shelf != "to-read" || wish.Ownership != "wishlist" || wish.Format != "ebook" {
+		t.Errorf("Unexpected wishlist book: %+v", wish)
+	}
+}
+
+// TestLibraryThingParseJSON verifies the JSON export maps like the TSV
+func TestLibraryThingParseJSON(t *testing.T) {
+	data := `{
+		"101": {
+			"title": "Kindred",
+			"primaryauthor": "Butler, Octavia E.",
+			"authors": [{"lf": "Butler, Octavia E.", "fl": "Octavia E. Butler"}, {"lf": "Due, Tananarive", "fl": "Tananarive Due"}],
+			"date": "1979",
+			"rating": 3,
+			"pages": "264 ",
+			"format": [{"code": "1", "text": "Paperback"}],
+			"tags": ["classics", "sci-fi"],
+			"collections": ["Currently reading", "Your library"],
+			"isbn": {"0": "0807083690", "2": "9780807083697"},
+			"entrydate": "2023-01-02"
+		}
+	}`
+
+	parsed, err := LibraryThingImporter{}.Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if len(parsed) != 1 {
+		t.Fatalf("Expected 1 book, got %d", len(parsed))
+	}
+	b := parsed[0].Book
+	if b.Title != "Kindred" || b.Author != "Octavia E. Butler" || b.AdditionalAuthors != "Tananarive Due" {
+		t.Errorf("Unexpected authors: %+v", b)
+	}
+	if b.ISBN != "0807083690" || b.ISBN13 != "9780807083697" || b.Pages != 264 || b.Rating != 3 {
+		t.Errorf("Unexpected identifiers or counts: %+v", b)
+	}
+	if b.Shelf != "currently-reading" || b.Ownership != "owned" || b.Format != "print" || b.Tags != "classics, sci-fi" {
+		t.Errorf("Unexpected shelf, ownership, format or tags: %+v", b)
+	}
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Loan is a book lent to someone
+type Loan struct {
+	ID           int64
+	BookID       int64
+	Title        string // of the lent book
+	Author       string
+	Borrower     string
+	LentDate     string // YYYY/MM/DD
+	DueDate      string // YYYY/MM/DD, empty when open-ended
+	ReturnedDate string // YYYY/MM/DD, empty while outstanding
+	CreatedAt    time.Time
+}
+
+// Outstanding reports whether the book is still with the borrower
+func (l *Loan) Outstanding() bool {
+	return l.ReturnedDate == ""
+}
+
+// Overdue reports whether an outstanding loan is past its due date. today
+// is a YYYY/MM/DD date.
+func (l *Loan) Overdue(today string) bool {
+	return l.Outstanding() && l.DueDate != "" && l.DueDate < today
+}
+
+// migrateLoans creates the loans table
+func migrateLoans(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE loans (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			book_id INTEGER NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+			borrower TEXT NOT NULL,
+			lent_date TEXT NOT NULL,
+			due_date TEXT DEFAULT '',
+			returned_date TEXT DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX idx_loans_book ON loans(book_id);
+	`)
+	return err
+}
+
+const loanColumns = `l.id, l.book_id, b.title, b.author, l.borrower, l.lent_date, l.due_date, l.returned_date, l.created_at`
+
+// queryLoans runs a query selecting loanColumns from loans l joined to books b
+func queryLoans(q querier, query string, args ...interface{}) ([]Loan, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var loans []Loan
+	for rows.Next() {
+		var l Loan
+		if err := rows.Scan(&l.ID, &l.BookID, &l.Title, &l.Author, &l.Borrower,
+			&l.LentDate, &l.DueDate, &l.ReturnedDate, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		loans = append(loans, l)
+	}
+	return loans, rows.Err()
+}
+
+// GetLoans returns loans, most recently lent first. With outstandingOnly,
+// returned loans are left out.
+func (s *Store) GetLoans(outstandingOnly bool) ([]Loan, error) {
+	query := `SELECT ` + loanColumns + ` FROM loans l JOIN books b ON b.id = l.book_id`
+	if outstandingOnly {
+		query += ` WHERE l.returned_date = ''`
+	}
+	query += ` ORDER BY l.lent_date DESC, l.id DESC`
+	return queryLoans(s.db, query)
+}
+
+// GetLoan returns a single loan by ID
+func (s *Store) GetLoan(id int64) (*Loan, error) {
+	loans, err := queryLoans(s.db, `SELECT `+loanColumns+` FROM loans l JOIN books b ON b.id = l.book_id WHERE l.id = ?`, id)
+	if err != nil || len(loans) == 0 {
+		return nil, err
+	}
+	return &loans[0], nil
+}
+
+// GetOutstandingLoan returns the loan a book is currently out on
+func (s *Store) GetOutstandingLoan(bookID int64) (*Loan, error) {
+	loans, err := queryLoans(s.db, `
+		SELECT `+loanColumns+` FROM loans l JOIN books b ON b.id = l.book_id
+		WHERE l.book_id = ? AND l.returned_date = ''
+	`, bookID)
+	if err != nil || len(loans) == 0 {
+		return nil, err
+	}
+	return &loans[0], nil
+}
+
+// CreateLoan records a book being lent and returns the loan ID
+func (s *Store) CreateLoan(l *Loan) (int64, error) {
+	result, err := s.db.Exec(`
+		INSERT INTO loans (book_id, borrower, lent_date, due_date) VALUES (?, ?, ?, ?)
+	`, l.BookID, l.Borrower, l.LentDate, l.DueDate)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// ReturnLoan marks a loan returned on the given date
+func (s *Store) ReturnLoan(id int64, returnedDate string) error {
+	_, err := s.db.Exec("UPDATE loans SET returned_date = ? WHERE id = ?", returnedDate, id)
+	return err
+}
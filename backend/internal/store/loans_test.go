@@ -0,0 +1,46 @@
+package store
+
+import "testing"
+
+// TestLoans verifies lending, listing and returning a book
+func TestLoans(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	bookID, _ := s.CreateBook(&Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+	id, err := s.CreateLoan(&Loan{BookID: bookID, Borrower: "Sam", LentDate: "2025/01/10", DueDate: "2025/02/10"})
+	if err != nil {
+		t.Fatalf("Failed to create loan: %v", err)
+	}
+
+	out, err := s.GetOutstandingLoan(bookID)
+	if err != nil || out == nil {
+		t.Fatalf("Expected an outstanding loan, got %v", err)
+	}
+	if out.Title != "Kindred" || out.Borrower != "Sam" {
+		t.Errorf("Expected Kindred lent to Sam, got %+v", out)
+	}
+	if !out.Overdue("2025/02/11") || out.Overdue("2025/02/10") {
+		t.Errorf("Expected overdue only after 2025/02/10")
+	}
+
+	if err := s.ReturnLoan(id, "2025/02/01"); err != nil {
+		t.Fatalf("Failed to return loan: %v", err)
+	}
+	loans, _ := s.GetLoans(true)
+	if len(loans) != 0 {
+		t.Errorf("Expected no outstanding loans, got %d", len(loans))
+	}
+	loans, _ = s.GetLoans(false)
+	if len(loans) != 1 || loans[0].ReturnedDate != "2025/02/01" || loans[0].Overdue("2030/01/01") {
+		t.Errorf("Expected one returned loan, got %+v", loans)
+	}
+
+	// Deleting the book removes its loans
+	if err := s.DeleteBook(bookID); err != nil {
+		t.Fatalf("Failed to delete book: %v", err)
+	}
+	if loans, _ = s.GetLoans(false); len(loans) != 0 {
+		t.Errorf("Expected loans removed with book, got %d", len(loans))
+	}
+}
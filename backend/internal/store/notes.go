@@ -0,0 +1,122 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Note kinds
+const (
+	NoteKindQuote     = "quote"
+	NoteKindHighlight = "highlight"
+	NoteKindNote      = "note"
+)
+
+// Note is a quote, highlight or free-form note attached to a book
+type Note struct {
+	ID        int64
+	BookID    int64
+	Kind      string
+	Text      string
+	Page      int
+	Location  string // e-reader location, e.g. "1234-1240"
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// migrateNotes creates the notes table
+func migrateNotes(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE notes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			book_id INTEGER NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+			kind TEXT NOT NULL DEFAULT 'note',
+			text TEXT NOT NULL,
+			page INTEGER DEFAULT 0,
+			location TEXT DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX idx_notes_book ON notes(book_id);
+	`)
+	return err
+}
+
+const noteColumns = `id, book_id, kind, text, page, location, created_at, updated_at`
+
+// queryNotes runs a query selecting noteColumns
+func queryNotes(q querier, query string, args ...interface{}) ([]Note, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []Note
+	for rows.Next() {
+		var n Note
+		if err := rows.Scan(&n.ID, &n.BookID, &n.Kind, &n.Text, &n.Page, &n.Location, &n.CreatedAt, &n.UpdatedAt); err != nil {
+			return nil, err
+		}
+		notes = append(notes, n)
+	}
+	return notes, rows.Err()
+}
+
+// GetNotes returns a book's notes in page/location order
+func (s *Store) GetNotes(bookID int64) ([]Note, error) {
+	return queryNotes(s.db, `SELECT `+noteColumns+` FROM notes WHERE book_id = ? ORDER BY page, location, id`, bookID)
+}
+
+// GetAllNotes returns every note grouped by book ID
+func (s *Store) GetAllNotes() (map[int64][]Note, error) {
+	notes, err := queryNotes(s.db, `SELECT `+noteColumns+` FROM notes ORDER BY book_id, page, location, id`)
+	if err != nil {
+		return nil, err
+	}
+	byBook := make(map[int64][]Note)
+	for _, n := range notes {
+		byBook[n.BookID] = append(byBook[n.BookID], n)
+	}
+	return byBook, nil
+}
+
+// GetNote returns a single note by ID
+func (s *Store) GetNote(id int64) (*Note, error) {
+	notes, err := queryNotes(s.db, `SELECT `+noteColumns+` FROM notes WHERE id = ?`, id)
+	if err != nil || len(notes) == 0 {
+		return nil, err
+	}
+	return &notes[0], nil
+}
+
+// CreateNote inserts a note and returns its ID
+func (s *Store) CreateNote(n *Note) (int64, error) {
+	return insertNote(s.db, n)
+}
+
+// insertNote inserts a note and returns its ID
+func insertNote(q querier, n *Note) (int64, error) {
+	result, err := q.Exec(`
+		INSERT INTO notes (book_id, kind, text, page, location) VALUES (?, ?, ?, ?, ?)
+	`, n.BookID, n.Kind, n.Text, n.Page, n.Location)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// UpdateNote updates a note's content
+func (s *Store) UpdateNote(n *Note) error {
+	_, err := s.db.Exec(`
+		UPDATE notes SET kind = ?, text = ?, page = ?, location = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, n.Kind, n.Text, n.Page, n.Location, n.ID)
+	return err
+}
+
+// DeleteNote removes a note by ID
+func (s *Store) DeleteNote(id int64) error {
+	_, err := s.db.Exec("DELETE FROM notes WHERE id = ?", id)
+	return err
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNotesCRUD verifies notes can be created, edited and deleted
+func TestNotesCRUD(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	bookID, err := s.CreateBook(&Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	id, err := s.CreateNote(&Note{BookID: bookID, Kind: NoteKindQuote, Text: "I lost an arm on my last trip home.", Page: 9})
+	if err != nil {
+		t.Fatalf("Failed to create note: %v", err)
+	}
+
+	note, err := s.GetNote(id)
+	if err != nil || note == nil {
+		t.Fatalf("Failed to get note: %v", err)
+	}
+	if note.Kind != NoteKindQuote || note.Page != 9 {
+		t.Errorf("Expected quote on page 9, got %+v", note)
+	}
+
+	note.Text = "I lost an arm on my last trip home. My left arm."
+	if err := s.UpdateNote(note); err != nil {
+		t.Fatalf("Failed to update note: %v", err)
+	}
+	notes, _ := s.GetNotes(bookID)
+	if len(notes) != 1 || !strings.HasSuffix(notes[0].Text, "My left arm.") {
+		t.Errorf("Expected updated note, got %+v", notes)
+	}
+
+	if err := s.DeleteNote(id); err != nil {
+		t.Fatalf("Failed to delete note: %v", err)
+	}
+	note, _ = s.GetNote(id)
+	if note != nil {
+		t.Errorf("Expected note to be deleted, got %+v", note)
+	}
+}
+
+// TestDeleteBookRemovesNotes verifies a book's notes go with it
+func TestDeleteBookRemovesNotes(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	bookID, _ := s.CreateBook(&Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+	s.CreateNote(&Note{BookID: bookID, Kind: NoteKindNote, Text: "Reread the epilogue"})
+
+	if err := s.DeleteBook(bookID); err != nil {
+		t.Fatalf("Failed to delete book: %v", err)
+	}
+	all, err := s.GetAllNotes()
+	if err != nil {
+		t.Fatalf("Failed to get notes: %v", err)
+	}
+	if len(all) != 0 {
+		t.Errorf("Expected no notes, got %d books with notes", len(all))
+	}
+	results, _ := s.Search("epilogue", 10)
+	if len(results) != 0 {
+		t.Errorf("Expected no search results, got %+v", results)
+	}
+}
+
+// TestSearch verifies books and notes are found and kept in step with edits
+func TestSearch(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	kindred, _ := s.CreateBook(&Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read"})
+	dune, _ := s.CreateBook(&Book{Title: "Dune", Author: "Frank Herbert", Shelf: "read", Review: "Spice and sandworms"})
+	s.CreateNote(&Note{BookID: kindred, Kind: NoteKindHighlight, Text: "Time travel to antebellum Maryland"})
+
+	// Title prefix match
+	results, err := s.Search("kind", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Kind != "book" || results[0].BookID != kindred {
+		t.Errorf("Expected Kindred, got %+v", results)
+	}
+
+	// Note text match carries its book's title
+	results, _ = s.Search("maryland", 10)
+	if len(results) != 1 || results[0].Kind != "note" || results[0].Title != "Kindred" {
+		t.Errorf("Expected Kindred note, got %+v", results)
+	}
+	if !strings.Contains(results[0].Snippet, "[Maryland]") {
+		t.Errorf("Expected highlighted snippet, got %q", results[0].Snippet)
+	}
+
+	// Reviews are searchable and follow updates
+	book, _ := s.GetBook(dune)
+	book.Review = "Politics in the desert"
+	if err := s.UpdateBook(book); err != nil {
+		t.Fatalf("Failed to update book: %v", err)
+	}
+	if results, _ = s.Search("sandworms", 10); len(results) != 0 {
+		t.Errorf("Expected stale review to be gone, got %+v", results)
+	}
+	if results, _ = s.Search("desert", 10); len(results) != 1 {
+		t.Errorf("Expected updated review to match, got %+v", results)
+	}
+
+	// FTS syntax in user input is treated as plain words
+	if _, err := s.Search(`"AND OR (`, 10); err != nil {
+		t.Errorf("Expected punctuation to be ignored, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Read is one reading of a book
+type Read struct {
+	ID           int64
+	BookID       int64
+	DateStarted  string
+	DateFinished string
+	Format       string
+	Notes        string
+	CreatedAt    time.Time
+}
+
+// migrateDateStarted adds the date a book was started
+func migrateDateStarted(tx *sql.Tx) error {
+	_, err := tx.Exec("ALTER TABLE books ADD COLUMN date_started TEXT DEFAULT ''")
+	return err
+}
+
+// migrateReads creates the reads table with one read per finished book
+func migrateReads(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE reads (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			book_id INTEGER NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+			date_started TEXT DEFAULT '',
+			date_finished TEXT DEFAULT '',
+			format TEXT DEFAULT '',
+			notes TEXT DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX idx_reads_book ON reads(book_id);
+		CREATE INDEX idx_reads_date_finished ON reads(date_finished);
+
+		INSERT INTO reads (book_id, date_finished)
+		SELECT id, date_read FROM books WHERE date_read != '';
+	`)
+	return err
+}
+
+const readColumns = `id, book_id, date_started, date_finished, format, notes, created_at`
+
+// scanRead reads a row selected with readColumns into a Read
+func scanRead(row rowScanner) (Read, error) {
+	var r Read
+	err := row.Scan(&r.ID, &r.BookID, &r.DateStarted, &r.DateFinished, &r.Format, &r.Notes, &r.CreatedAt)
+	return r, err
+}
+
+// queryReads runs a query selecting readColumns
+func queryReads(q querier, query string, args ...interface{}) ([]Read, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reads []Read
+	for rows.Next() {
+		r, err := scanRead(rows)
+		if err != nil {
+			return nil, err
+		}
+		reads = append(reads, r)
+	}
+	return reads, rows.Err()
+}
+
+// GetReads returns the reads of a book, oldest first. Undated reads sort first.
+func (s *Store) GetReads(bookID int64) ([]Read, error) {
+	return queryReads(s.db, `SELECT `+readColumns+` FROM reads WHERE book_id = ? ORDER BY date_finished, id`, bookID)
+}
+
+// GetAllReads returns every read grouped by book ID
+func (s *Store) GetAllReads() (map[int64][]Read, error) {
+	reads, err := queryReads(s.db, `SELECT `+readColumns+` FROM reads ORDER BY book_id, date_finished, id`)
+	if err != nil {
+		return nil, err
+	}
+	byBook := make(map[int64][]Read)
+	for _, r := range reads {
+		byBook[r.BookID] = append(byBook[r.BookID], r)
+	}
+	return byBook, nil
+}
+
+// AddRead records a read of a book and returns its ID
+func (s *Store) AddRead(r *Read) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	id, err := insertRead(tx, r)
+	if err != nil {
+		return 0, err
+	}
+	if err := refreshDateRead(tx, r.BookID); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// DeleteRead removes a read from a book
+func (s *Store) DeleteRead(bookID, readID int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM reads WHERE id = ? AND book_id = ?", readID, bookID); err != nil {
+		return err
+	}
+	if err := refreshDateRead(tx, bookID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertRead inserts a read row
+func insertRead(q querier, r *Read) (int64, error) {
+	result, err := q.Exec(`
+		INSERT INTO reads (book_id, date_started, date_finished, format, notes)
+		VALUES (?, ?, ?, ?, ?)
+	`, r.BookID, r.DateStarted, r.DateFinished, r.Format, r.Notes)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// refreshDateRead sets the book's date_read and date_started from its most
+// recently finished read. A book with no finished reads keeps its start date,
+// and a start date cleared on the book stays cleared.
+func refreshDateRead(q querier, bookID int64) error {
+	_, err := q.Exec(`
+		UPDATE books SET
+			date_read = COALESCE(
+				(SELECT MAX(date_finished) FROM reads WHERE book_id = ?1 AND date_finished != ''), ''),
+			date_started = CASE WHEN date_started = '' THEN '' ELSE COALESCE(
+				(SELECT date_started FROM reads WHERE book_id = ?1 AND date_finished != ''
+				 ORDER BY date_finished DESC, id DESC LIMIT 1), date_started) END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?1
+	`, bookID)
+	return err
+}
+
+// syncLatestRead keeps the most recent read in step with a book's DateRead
+// and DateStarted when they are edited directly: the latest read takes the
+// new dates, or a first read is recorded. Clearing DateRead leaves the
+// history alone, as does an empty DateStarted.
+func syncLatestRead(q querier, bookID int64, dateStarted, dateRead string) error {
+	if dateRead == "" {
+		return nil
+	}
+
+	var readID int64
+	err := q.QueryRow(`
+		SELECT id FROM reads WHERE book_id = ?
+		ORDER BY date_finished DESC, id DESC LIMIT 1
+	`, bookID).Scan(&readID)
+	if err == sql.ErrNoRows {
+		_, err = insertRead(q, &Read{BookID: bookID, DateStarted: dateStarted, DateFinished: dateRead})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	_, err = q.Exec(`
+		UPDATE reads SET date_finished = ?, date_started = COALESCE(NULLIF(?, ''), date_started)
+		WHERE id = ?
+	`, dateRead, dateStarted, readID)
+	return err
+}
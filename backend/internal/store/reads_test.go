@@ -0,0 +1,146 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// TestCreateBookRecordsRead verifies a dated book gets its first read
+func TestCreateBookRecordsRead(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	id, err := s.CreateBook(&Book{Title: "Kindred", Author: "Octavia E. Butler", DateRead: "2023/02/01", Shelf: "read"})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	reads, err := s.GetReads(id)
+	if err != nil {
+		t.Fatalf("Failed to get reads: %v", err)
+	}
+	if len(reads) != 1 || reads[0].DateFinished != "2023/02/01" {
+		t.Errorf("Expected one read finished 2023/02/01, got %+v", reads)
+	}
+}
+
+// TestAddReadUpdatesDateRead verifies a re-read becomes the book's DateRead
+func TestAddReadUpdatesDateRead(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	id, err := s.CreateBook(&Book{Title: "Kindred", Author: "Octavia E. Butler", DateRead: "2023/02/01", Shelf: "read"})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	readID, err := s.AddRead(&Read{BookID: id, DateStarted: "2025/03/01", DateFinished: "2025/03/09", Format: "audiobook"})
+	if err != nil {
+		t.Fatalf("Failed to add read: %v", err)
+	}
+
+	book, _ := s.GetBook(id)
+	if book.DateRead != "2025/03/09" {
+		t.Errorf("Expected DateRead 2025/03/09, got %q", book.DateRead)
+	}
+
+	// Deleting the re-read restores the earlier date
+	if err := s.DeleteRead(id, readID); err != nil {
+		t.Fatalf("Failed to delete read: %v", err)
+	}
+	book, _ = s.GetBook(id)
+	if book.DateRead != "2023/02/01" {
+		t.Errorf("Expected DateRead 2023/02/01 after delete, got %q", book.DateRead)
+	}
+}
+
+// TestUpdateBookMovesLatestRead verifies editing DateRead corrects the latest read
+func TestUpdateBookMovesLatestRead(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	book := &Book{Title: "Kindred", Author: "Octavia E. Butler", DateRead: "2023/02/01", Shelf: "read"}
+	id, err := s.CreateBook(book)
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	book.ID = id
+	book.DateRead = "2023/02/11"
+	if err := s.UpdateBook(book); err != nil {
+		t.Fatalf("Failed to update book: %v", err)
+	}
+
+	reads, _ := s.GetReads(id)
+	if len(reads) != 1 || reads[0].DateFinished != "2023/02/11" {
+		t.Errorf("Expected the single read to move to 2023/02/11, got %+v", reads)
+	}
+}
+
+// TestImportHonoursReadCount verifies Goodreads Read Count creates extra reads
+func TestImportHonoursReadCount(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	imported, err := s.ImportFromJSON([]books.Book{
+		{Title: "Dune", Author: "Frank Herbert", DateRead: "2024/06/01", Shelf: "read", GoodreadsReadCount: float64(3)},
+	})
+	if err != nil || imported != 1 {
+		t.Fatalf("Expected 1 imported book, got %d (err %v)", imported, err)
+	}
+
+	all, _ := s.GetAllBooks()
+	reads, err := s.GetReads(all[0].ID)
+	if err != nil {
+		t.Fatalf("Failed to get reads: %v", err)
+	}
+	if len(reads) != 3 {
+		t.Errorf("Expected 3 reads, got %d", len(reads))
+	}
+}
+
+// TestDateStartedRoundTrip verifies DateStarted is stored on the book and its read
+func TestDateStartedRoundTrip(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	id, err := s.CreateBook(&Book{
+		Title: "Kindred", Author: "Octavia E. Butler",
+		DateStarted: "2023/01/20", DateRead: "2023/02/01", Shelf: "read",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	book, _ := s.GetBook(id)
+	if book.DateStarted != "2023/01/20" {
+		t.Errorf("Expected DateStarted 2023/01/20, got %q", book.DateStarted)
+	}
+
+	reads, _ := s.GetReads(id)
+	if len(reads) != 1 || reads[0].DateStarted != "2023/01/20" {
+		t.Errorf("Expected read started 2023/01/20, got %+v", reads)
+	}
+
+	// A newer read carries its own start date onto the book
+	if _, err := s.AddRead(&Read{BookID: id, DateStarted: "2025/05/01", DateFinished: "2025/05/04"}); err != nil {
+		t.Fatalf("Failed to add read: %v", err)
+	}
+	book, _ = s.GetBook(id)
+	if book.DateStarted != "2025/05/01" {
+		t.Errorf("Expected DateStarted 2025/05/01, got %q", book.DateStarted)
+	}
+
+	// A start date cleared on the book isn't restored from the read
+	book.DateStarted = ""
+	if err := s.UpdateBook(book); err != nil {
+		t.Fatalf("Failed to update book: %v", err)
+	}
+	if _, err := s.AddRead(&Read{BookID: id, DateFinished: "2021/01/01"}); err != nil {
+		t.Fatalf("Failed to add read: %v", err)
+	}
+	if book, _ = s.GetBook(id); book.DateStarted != "" {
+		t.Errorf("Expected DateStarted to stay cleared, got %q", book.DateStarted)
+	}
+}
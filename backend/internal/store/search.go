@@ -0,0 +1,107 @@
+package store
+
+import (
+	"database/sql"
+	"strings"
+	"unicode"
+)
+
+// SearchResult is a book or note matching a search query
+type SearchResult struct {
+	Kind    string // "book" or "note"
+	RefID   int64  // book or note ID
+	BookID  int64
+	Title   string // title of the book the match belongs to
+	Snippet string // matching text with hits wrapped in [ ]
+}
+
+// migrateSearch creates a full-text index over books and notes, kept in step
+// by triggers, and fills it from existing rows
+func migrateSearch(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE VIRTUAL TABLE search_index USING fts5(
+			kind UNINDEXED, ref_id UNINDEXED, book_id UNINDEXED,
+			title, authors, body,
+			tokenize = 'unicode61 remove_diacritics 2'
+		);
+
+		CREATE TRIGGER books_search_insert AFTER INSERT ON books BEGIN
+			INSERT INTO search_index (kind, ref_id, book_id, title, authors, body)
+			VALUES ('book', new.id, new.id, new.title,
+			        new.author || ' ' || new.additional_authors, new.review);
+		END;
+		CREATE TRIGGER books_search_update AFTER UPDATE OF title, author, additional_authors, review ON books BEGIN
+			DELETE FROM search_index WHERE kind = 'book' AND ref_id = old.id;
+			INSERT INTO search_index (kind, ref_id, book_id, title, authors, body)
+			VALUES ('book', new.id, new.id, new.title,
+			        new.author || ' ' || new.additional_authors, new.review);
+		END;
+		CREATE TRIGGER books_search_delete AFTER DELETE ON books BEGIN
+			DELETE FROM search_index WHERE book_id = old.id;
+		END;
+
+		CREATE TRIGGER notes_search_insert AFTER INSERT ON notes BEGIN
+			INSERT INTO search_index (kind, ref_id, book_id, title, authors, body)
+			VALUES ('note', new.id, new.book_id, '', '', new.text);
+		END;
+		CREATE TRIGGER notes_search_update AFTER UPDATE OF text ON notes BEGIN
+			DELETE FROM search_index WHERE kind = 'note' AND ref_id = old.id;
+			INSERT INTO search_index (kind, ref_id, book_id, title, authors, body)
+			VALUES ('note', new.id, new.book_id, '', '', new.text);
+		END;
+		CREATE TRIGGER notes_search_delete AFTER DELETE ON notes BEGIN
+			DELETE FROM search_index WHERE kind = 'note' AND ref_id = old.id;
+		END;
+
+		INSERT INTO search_index (kind, ref_id, book_id, title, authors, body)
+		SELECT 'book', id, id, title, author || ' ' || additional_authors, review FROM books;
+		INSERT INTO search_index (kind, ref_id, book_id, title, authors, body)
+		SELECT 'note', id, book_id, '', '', text FROM notes;
+	`)
+	return err
+}
+
+// ftsQuery turns free text into an FTS5 query matching every word as a
+// prefix, so user input can't trip over FTS5 syntax
+func ftsQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, len(words))
+	for i, w := range words {
+		terms[i] = `"` + w + `"*`
+	}
+	return strings.Join(terms, " ")
+}
+
+// Search returns books and notes matching text, best matches first
+func (s *Store) Search(text string, limit int) ([]SearchResult, error) {
+	query := ftsQuery(text)
+	if query == "" {
+		return nil, nil
+	}
+
+	rows, err := s.db.Query(`
+		SELECT si.kind, si.ref_id, si.book_id, COALESCE(b.title, ''),
+		       snippet(search_index, -1, '[', ']', '…', 12)
+		FROM search_index si
+		LEFT JOIN books b ON b.id = si.book_id
+		WHERE search_index MATCH ?
+		ORDER BY bm25(search_index, 0, 0, 0, 10.0, 5.0, 1.0)
+		LIMIT ?
+	`, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		if err := rows.Scan(&r.Kind, &r.RefID, &r.BookID, &r.Title, &r.Snippet); err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"database/sql"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// migrateSeries adds the series columns and backfills them from existing titles
+func migrateSeries(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+		ALTER TABLE books ADD COLUMN series TEXT DEFAULT '';
+		ALTER TABLE books ADD COLUMN series_position REAL DEFAULT 0;
+		CREATE INDEX IF NOT EXISTS idx_books_series ON books(series);
+	`); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query("SELECT id, title FROM books WHERE title LIKE '%#%'")
+	if err != nil {
+		return err
+	}
+	type titled struct {
+		id    int64
+		title string
+	}
+	var candidates []titled
+	for rows.Next() {
+		var t titled
+		if err := rows.Scan(&t.id, &t.title); err != nil {
+			rows.Close()
+			return err
+		}
+		candidates = append(candidates, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, t := range candidates {
+		_, series := books.ParseSeries(t.title)
+		if len(series) == 0 {
+			continue
+		}
+		if _, err := tx.Exec("UPDATE books SET series = ?, series_position = ? WHERE id = ?",
+			series[0].Name, series[0].Position, t.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fillSeries derives the series columns from the title when they are unset
+func fillSeries(b *Book) {
+	if b.Series != "" {
+		return
+	}
+	if _, series := books.ParseSeries(b.Title); len(series) > 0 {
+		b.Series = series[0].Name
+		b.SeriesPosition = series[0].Position
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/kristenwomack/reading-app/backend/internal/books"
 	_ "modernc.org/sqlite"
 )
 
@@ -20,11 +21,28 @@ type Book struct {
 	Pages                   int
 	YearPublished           int
 	OriginalPublicationYear int
+	DateStarted             string
 	DateRead                string
 	DateAdded               string
 	Shelf                   string
 	Review                  string
 	CoverURL                string
+	Series                  string
+	SeriesPosition          float64
+	Format                  string
+	DurationMinutes         int
+	DNFDate                 string
+	DNFPage                 int
+	DNFPercent              float64
+	DNFReason               string
+	Ownership               string // owned, borrowed, library or wishlist
+	OwnedFormat             string
+	PurchaseDate            string
+	PurchasePrice           float64
+	ShelfLocation           string  // where the copy lives, e.g. "Study, shelf 3"
+	Tags                    string  // comma-separated, e.g. "favorites, sci-fi"
+	Rating                  float64 // 0.25-5 in quarter stars, 0 when unrated
+	CitationKey             string  // e.g. "butler1979kindred", fixed once assigned
 	CreatedAt               time.Time
 	UpdatedAt               time.Time
 }
@@ -46,6 +64,11 @@ func New(dbPath string) (*Store, error) {
 		return nil, fmt.Errorf("failed to set pragmas: %w", err)
 	}
 
+	// Each connection to ":memory:" gets its own database, so keep to one
+	if dbPath == ":memory:" {
+		db.SetMaxOpenConns(1)
+	}
+
 	store := &Store{db: db}
 	if err := store.migrate(); err != nil {
 		return nil, fmt.Errorf("failed to migrate: %w", err)
@@ -59,8 +82,42 @@ func (s *Store) Close() error {
 	return s.db.Close()
 }
 
+// migrations are applied in order after the base schema. The database's
+// PRAGMA user_version records how many have already run.
+var migrations = []func(tx *sql.Tx) error{
+	migrateSeries,
+	migrateAuthors,
+	migrateReads,
+	migrateDateStarted,
+	migrateFormat,
+	migrateDNF,
+	migrateNotes,
+	migrateSearch,
+	migrateLoans,
+	migrateOwnership,
+	migrateTags,
+	migrateRating,
+	migratePruneAuthors,
+	migrateCitationKeys,
+	normalizeDNFShelves, // spellings migrateDNF missed before it used NormalizeShelf
+	migrateAuthorInitials,
+}
+
+// SchemaVersion returns the number of migrations applied to the database
+func (s *Store) SchemaVersion() (int, error) {
+	var version int
+	err := s.db.QueryRow("PRAGMA user_version").Scan(&version)
+	return version, err
+}
+
 // migrate runs database migrations
 func (s *Store) migrate() error {
+	return s.migrateTo(len(migrations))
+}
+
+// migrateTo creates the base schema and applies migrations up to version,
+// so a backup from an older version can be loaded before upgrading it
+func (s *Store) migrateTo(target int) error {
 	schema := `
 	CREATE TABLE IF NOT EXISTS books (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -96,19 +153,147 @@ func (s *Store) migrate() error {
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 	`
-	_, err := s.db.Exec(schema)
+	if _, err := s.db.Exec(schema); err != nil {
+		return err
+	}
+
+	version, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	for i := version; i < target; i++ {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		if err := migrations[i](tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateFormat adds the book format and audiobook duration
+func migrateFormat(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE books ADD COLUMN format TEXT DEFAULT '';
+		ALTER TABLE books ADD COLUMN duration_minutes INTEGER DEFAULT 0;
+	`)
+	return err
+}
+
+// migrateDNF adds abandonment details and moves Goodreads-style
+// did-not-finish shelves onto the dnf shelf
+func migrateDNF(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE books ADD COLUMN dnf_date TEXT DEFAULT '';
+		ALTER TABLE books ADD COLUMN dnf_page INTEGER DEFAULT 0;
+		ALTER TABLE books ADD COLUMN dnf_percent REAL DEFAULT 0;
+		ALTER TABLE books ADD COLUMN dnf_reason TEXT DEFAULT '';
+	`)
+	if err != nil {
+		return err
+	}
+	return normalizeDNFShelves(tx)
+}
+
+// normalizeDNFShelves moves books on any did-not-finish spelling that
+// books.NormalizeShelf recognises, such as "Did Not Finish", to dnf
+func normalizeDNFShelves(tx *sql.Tx) error {
+	rows, err := tx.Query("SELECT DISTINCT shelf FROM books")
+	if err != nil {
+		return err
+	}
+	var shelves []string
+	for rows.Next() {
+		var shelf string
+		if err := rows.Scan(&shelf); err != nil {
+			rows.Close()
+			return err
+		}
+		if shelf != books.ShelfDNF && books.NormalizeShelf(shelf) == books.ShelfDNF {
+			shelves = append(shelves, shelf)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, shelf := range shelves {
+		if _, err := tx.Exec("UPDATE books SET shelf = ? WHERE shelf = ?", books.ShelfDNF, shelf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateOwnership adds whether and how we own a copy, and where it lives
+func migrateOwnership(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE books ADD COLUMN ownership TEXT DEFAULT '';
+		ALTER TABLE books ADD COLUMN owned_format TEXT DEFAULT '';
+		ALTER TABLE books ADD COLUMN purchase_date TEXT DEFAULT '';
+		ALTER TABLE books ADD COLUMN purchase_price REAL DEFAULT 0;
+		ALTER TABLE books ADD COLUMN shelf_location TEXT DEFAULT '';
+	`)
+	return err
+}
+
+// migrateTags adds free-form tags, Goodreads' non-exclusive shelves
+func migrateTags(tx *sql.Tx) error {
+	_, err := tx.Exec("ALTER TABLE books ADD COLUMN tags TEXT DEFAULT ''")
 	return err
 }
 
+// migrateRating adds our star rating, Goodreads' My Rating
+func migrateRating(tx *sql.Tx) error {
+	_, err := tx.Exec("ALTER TABLE books ADD COLUMN rating REAL DEFAULT 0")
+	return err
+}
+
+// bookColumns lists the columns of books aliased as b, in the order scanBook expects
+const bookColumns = `b.id, b.title, b.author, b.additional_authors, b.isbn, b.isbn13,
+	b.publisher, b.pages, b.year_published, b.original_publication_year,
+	b.date_read, b.date_added, b.shelf, b.review, b.cover_url,
+	b.series, b.series_position, b.date_started, b.format, b.duration_minutes,
+	b.dnf_date, b.dnf_page, b.dnf_percent, b.dnf_reason,
+	b.ownership, b.owned_format, b.purchase_date, b.purchase_price, b.shelf_location,
+	b.tags, b.rating, b.citation_key, b.created_at, b.updated_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanBook reads a row selected with bookColumns into a Book. Any extra
+// destinations receive columns selected after bookColumns.
+func scanBook(row rowScanner, extra ...interface{}) (Book, error) {
+	var b Book
+	dest := []interface{}{
+		&b.ID, &b.Title, &b.Author, &b.AdditionalAuthors, &b.ISBN, &b.ISBN13,
+		&b.Publisher, &b.Pages, &b.YearPublished, &b.OriginalPublicationYear,
+		&b.DateRead, &b.DateAdded, &b.Shelf, &b.Review, &b.CoverURL,
+		&b.Series, &b.SeriesPosition, &b.DateStarted, &b.Format, &b.DurationMinutes,
+		&b.DNFDate, &b.DNFPage, &b.DNFPercent, &b.DNFReason,
+		&b.Ownership, &b.OwnedFormat, &b.PurchaseDate, &b.PurchasePrice, &b.ShelfLocation,
+		&b.Tags, &b.Rating, &b.CitationKey, &b.CreatedAt, &b.UpdatedAt,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return b, err
+}
+
 // GetAllBooks returns all books from the database
 func (s *Store) GetAllBooks() ([]Book, error) {
-	rows, err := s.db.Query(`
-		SELECT id, title, author, additional_authors, isbn, isbn13, publisher,
-		       pages, year_published, original_publication_year, date_read,
-		       date_added, shelf, review, cover_url, created_at, updated_at
-		FROM books
-		ORDER BY date_read DESC
-	`)
+	rows, err := s.db.Query(`SELECT ` + bookColumns + ` FROM books b ORDER BY b.date_read DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -116,13 +301,7 @@ func (s *Store) GetAllBooks() ([]Book, error) {
 
 	var books []Book
 	for rows.Next() {
-		var b Book
-		err := rows.Scan(
-			&b.ID, &b.Title, &b.Author, &b.AdditionalAuthors, &b.ISBN, &b.ISBN13,
-			&b.Publisher, &b.Pages, &b.YearPublished, &b.OriginalPublicationYear,
-			&b.DateRead, &b.DateAdded, &b.Shelf, &b.Review, &b.CoverURL,
-			&b.CreatedAt, &b.UpdatedAt,
-		)
+		b, err := scanBook(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -133,18 +312,7 @@ func (s *Store) GetAllBooks() ([]Book, error) {
 
 // GetBook returns a single book by ID
 func (s *Store) GetBook(id int64) (*Book, error) {
-	var b Book
-	err := s.db.QueryRow(`
-		SELECT id, title, author, additional_authors, isbn, isbn13, publisher,
-		       pages, year_published, original_publication_year, date_read,
-		       date_added, shelf, review, cover_url, created_at, updated_at
-		FROM books WHERE id = ?
-	`, id).Scan(
-		&b.ID, &b.Title, &b.Author, &b.AdditionalAuthors, &b.ISBN, &b.ISBN13,
-		&b.Publisher, &b.Pages, &b.YearPublished, &b.OriginalPublicationYear,
-		&b.DateRead, &b.DateAdded, &b.Shelf, &b.Review, &b.CoverURL,
-		&b.CreatedAt, &b.UpdatedAt,
-	)
+	b, err := scanBook(s.db.QueryRow(`SELECT `+bookColumns+` FROM books b WHERE b.id = ?`, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -156,39 +324,145 @@ func (s *Store) GetBook(id int64) (*Book, error) {
 
 // CreateBook inserts a new book and returns its ID
 func (s *Store) CreateBook(b *Book) (int64, error) {
-	result, err := s.db.Exec(`
+	return s.createBookWith(b, nil, nil)
+}
+
+// createBookWith inserts a book together with earlier reads and notes in one
+// transaction, so an import never leaves a book half-added
+func (s *Store) createBookWith(b *Book, reads []Read, notes []Note) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	id, err := insertBook(tx, b)
+	if err != nil {
+		return 0, err
+	}
+	for _, rd := range reads {
+		rd.BookID = id
+		if _, err := insertRead(tx, &rd); err != nil {
+			return 0, err
+		}
+	}
+	for _, n := range notes {
+		n.BookID = id
+		if _, err := insertNote(tx, &n); err != nil {
+			return 0, err
+		}
+	}
+	return id, tx.Commit()
+}
+
+// insertBook inserts a book with its author links and latest read
+func insertBook(q querier, b *Book) (int64, error) {
+	fillSeries(b)
+	result, err := q.Exec(`
 		INSERT INTO books (title, author, additional_authors, isbn, isbn13, publisher,
 		                   pages, year_published, original_publication_year, date_read,
-		                   date_added, shelf, review, cover_url)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		                   date_added, shelf, review, cover_url, series, series_position,
+		                   date_started, format, duration_minutes, dnf_date, dnf_page,
+		                   dnf_percent, dnf_reason, ownership, owned_format, purchase_date,
+		                   purchase_price, shelf_location, tags, rating)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, b.Title, b.Author, b.AdditionalAuthors, b.ISBN, b.ISBN13, b.Publisher,
 		b.Pages, b.YearPublished, b.OriginalPublicationYear, b.DateRead,
-		b.DateAdded, b.Shelf, b.Review, b.CoverURL)
+		b.DateAdded, b.Shelf, b.Review, b.CoverURL, b.Series, b.SeriesPosition,
+		b.DateStarted, b.Format, b.DurationMinutes, b.DNFDate, b.DNFPage,
+		b.DNFPercent, b.DNFReason, b.Ownership, b.OwnedFormat, b.PurchaseDate,
+		b.PurchasePrice, b.ShelfLocation, b.Tags, b.Rating)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
 	if err != nil {
 		return 0, err
 	}
-	return result.LastInsertId()
+	if err := linkAuthors(q, id, b.Author, b.AdditionalAuthors); err != nil {
+		return 0, err
+	}
+	if err := syncLatestRead(q, id, b.DateStarted, b.DateRead); err != nil {
+		return 0, err
+	}
+	b.ID = id
+	if b.CitationKey, err = assignCitationKey(q, b); err != nil {
+		return 0, err
+	}
+	return id, nil
 }
 
 // UpdateBook updates an existing book
 func (s *Store) UpdateBook(b *Book) error {
-	_, err := s.db.Exec(`
+	fillSeries(b)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
 		UPDATE books SET
 			title = ?, author = ?, additional_authors = ?, isbn = ?, isbn13 = ?,
 			publisher = ?, pages = ?, year_published = ?, original_publication_year = ?,
 			date_read = ?, date_added = ?, shelf = ?, review = ?, cover_url = ?,
-			updated_at = CURRENT_TIMESTAMP
+			series = ?, series_position = ?, date_started = ?, format = ?,
+			duration_minutes = ?, dnf_date = ?, dnf_page = ?, dnf_percent = ?,
+			dnf_reason = ?, ownership = ?, owned_format = ?, purchase_date = ?,
+			purchase_price = ?, shelf_location = ?, tags = ?, rating = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`, b.Title, b.Author, b.AdditionalAuthors, b.ISBN, b.ISBN13,
 		b.Publisher, b.Pages, b.YearPublished, b.OriginalPublicationYear,
-		b.DateRead, b.DateAdded, b.Shelf, b.Review, b.CoverURL, b.ID)
-	return err
+		b.DateRead, b.DateAdded, b.Shelf, b.Review, b.CoverURL,
+		b.Series, b.SeriesPosition, b.DateStarted, b.Format, b.DurationMinutes,
+		b.DNFDate, b.DNFPage, b.DNFPercent, b.DNFReason, b.Ownership, b.OwnedFormat,
+		b.PurchaseDate, b.PurchasePrice, b.ShelfLocation, b.Tags, b.Rating, b.ID)
+	if err != nil {
+		return err
+	}
+	if err := linkAuthors(tx, b.ID, b.Author, b.AdditionalAuthors); err != nil {
+		return err
+	}
+	if err := syncLatestRead(tx, b.ID, b.DateStarted, b.DateRead); err != nil {
+		return err
+	}
+	if b.DateRead != "" {
+		if err := refreshDateRead(tx, b.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 // DeleteBook removes a book by ID
 func (s *Store) DeleteBook(id int64) error {
-	_, err := s.db.Exec("DELETE FROM books WHERE id = ?", id)
-	return err
+	// Dependent rows are removed explicitly since foreign_keys is per-connection
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	authors, err := bookAuthorIDs(tx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range []string{
+		"DELETE FROM book_authors WHERE book_id = ?",
+		"DELETE FROM reads WHERE book_id = ?",
+		"DELETE FROM notes WHERE book_id = ?",
+		"DELETE FROM loans WHERE book_id = ?",
+		"DELETE FROM books WHERE id = ?",
+	} {
+		if _, err := tx.Exec(stmt, id); err != nil {
+			return err
+		}
+	}
+	if err := pruneAuthors(tx, authors); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // GetSetting retrieves a setting value
@@ -236,6 +510,25 @@ func (s *Store) GetGoal(year int) (*Goal, error) {
 	return &g, nil
 }
 
+// GetGoals returns every goal keyed by year
+func (s *Store) GetGoals() (map[int]int, error) {
+	rows, err := s.db.Query("SELECT year, book_target FROM goals")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	goals := make(map[int]int)
+	for rows.Next() {
+		var year, target int
+		if err := rows.Scan(&year, &target); err != nil {
+			return nil, err
+		}
+		goals[year] = target
+	}
+	return goals, rows.Err()
+}
+
 // SetGoal creates or updates a goal for a year
 func (s *Store) SetGoal(year, bookTarget int) error {
 	_, err := s.db.Exec(`
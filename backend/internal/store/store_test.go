@@ -1,6 +1,7 @@
 package store
 
 import (
+	"database/sql"
 	"testing"
 )
 
@@ -440,3 +441,172 @@ func TestSetSettingUpdate(t *testing.T) {
 		t.Errorf("Expected value %q, got %q", "dark", value)
 	}
 }
+
+// TestCreateBookDerivesSeries verifies series columns are filled from the title
+func TestCreateBookDerivesSeries(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	id, err := s.CreateBook(&Book{Title: "Dawn (Xenogenesis, #1)", Author: "Octavia E. Butler", Shelf: "read"})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	book, err := s.GetBook(id)
+	if err != nil {
+		t.Fatalf("Failed to get book: %v", err)
+	}
+
+	if book.Series != "Xenogenesis" {
+		t.Errorf("Expected series %q, got %q", "Xenogenesis", book.Series)
+	}
+	if book.SeriesPosition != 1 {
+		t.Errorf("Expected series position 1, got %v", book.SeriesPosition)
+	}
+}
+
+// TestMigrateSeriesBackfill verifies the series migration backfills existing rows
+func TestMigrateSeriesBackfill(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	// Insert a row directly, bypassing CreateBook's series derivation
+	if _, err := s.db.Exec(`INSERT INTO books (title, author) VALUES ('Imago (Xenogenesis, #3)', 'Octavia E. Butler')`); err != nil {
+		t.Fatalf("Failed to insert book: %v", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	if _, err := tx.Exec("ALTER TABLE books DROP COLUMN series_position; DROP INDEX idx_books_series; ALTER TABLE books DROP COLUMN series"); err != nil {
+		t.Fatalf("Failed to drop series columns: %v", err)
+	}
+	if err := migrateSeries(tx); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	books, err := s.GetAllBooks()
+	if err != nil {
+		t.Fatalf("Failed to get books: %v", err)
+	}
+	if len(books) != 1 || books[0].Series != "Xenogenesis" || books[0].SeriesPosition != 3 {
+		t.Errorf("Expected backfilled Xenogenesis #3, got %+v", books)
+	}
+}
+
+// TestSchemaVersion verifies all migrations are recorded
+func TestSchemaVersion(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	version, err := s.SchemaVersion()
+	if err != nil {
+		t.Fatalf("Failed to get schema version: %v", err)
+	}
+	if version != len(migrations) {
+		t.Errorf("Expected schema version %d, got %d", len(migrations), version)
+	}
+}
+
+// TestBookFormatRoundTrip verifies format and duration are stored
+func TestBookFormatRoundTrip(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	id, err := s.CreateBook(&Book{Title: "Project Hail Mary", Author: "Andy Weir", Format: "audiobook", DurationMinutes: 970, Shelf: "read"})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	book, err := s.GetBook(id)
+	if err != nil {
+		t.Fatalf("Failed to get book: %v", err)
+	}
+	if book.Format != "audiobook" || book.DurationMinutes != 970 {
+		t.Errorf("Expected audiobook of 970 minutes, got %q of %d", book.Format, book.DurationMinutes)
+	}
+}
+
+// TestOwnershipFieldsRoundTrip verifies ownership details are stored
+func TestOwnershipFieldsRoundTrip(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	id, err := s.CreateBook(&Book{
+		Title: "Dune", Author: "Frank Herbert", Shelf: "read",
+		Ownership: "owned", OwnedFormat: "print", PurchaseDate: "2024/05/01",
+		PurchasePrice: 18.99, ShelfLocation: "Study, shelf 3",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	book, _ := s.GetBook(id)
+	if book.Ownership != "owned" || book.OwnedFormat != "print" || book.PurchaseDate != "2024/05/01" ||
+		book.PurchasePrice != 18.99 || book.ShelfLocation != "Study, shelf 3" {
+		t.Errorf("Ownership fields not stored: %+v", book)
+	}
+}
+
+// TestRatingRoundTrip verifies quarter-star ratings are stored as reals
+func TestRatingRoundTrip(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	id, err := s.CreateBook(&Book{Title: "Kindred", Author: "Octavia E. Butler", Shelf: "read", Rating: 3.75})
+	if err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	book, _ := s.GetBook(id)
+	if book.Rating != 3.75 {
+		t.Errorf("Expected 3.75 stars, got %g", book.Rating)
+	}
+	var kind string
+	if err := s.db.QueryRow("SELECT typeof(rating) FROM books WHERE id = ?", id).Scan(&kind); err != nil {
+		t.Fatalf("Failed to read rating type: %v", err)
+	}
+	if kind != "real" {
+		t.Errorf("Expected rating stored as real, got %s", kind)
+	}
+}
+
+// TestMigrateDNFShelves verifies every did-not-finish spelling that
+// NormalizeShelf knows is moved to dnf, including ones with spaces
+func TestMigrateDNFShelves(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	s := &Store{db: db}
+	defer s.Close()
+
+	// Just before migrateDNF
+	if err := s.migrateTo(5); err != nil {
+		t.Fatalf("Failed to create old schema: %v", err)
+	}
+	for _, shelf := range []string{"Did Not Finish", "abandoned", "read"} {
+		if _, err := db.Exec("INSERT INTO books (title, author, shelf) VALUES (?, 'A', ?)", shelf, shelf); err != nil {
+			t.Fatalf("Failed to insert book: %v", err)
+		}
+	}
+	if err := s.migrate(); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	all, _ := s.GetAllBooks()
+	for _, b := range all {
+		want := "dnf"
+		if b.Title == "read" {
+			want = "read"
+		}
+		if b.Shelf != want {
+			t.Errorf("%s: expected shelf %s, got %s", b.Title, want, b.Shelf)
+		}
+	}
+}
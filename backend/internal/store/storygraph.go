@@ -0,0 +1,119 @@
+package store
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kristenwomack/reading-app/backend/internal/books"
+)
+
+// StoryGraphImporter reads The StoryGraph's CSV export
+type StoryGraphImporter struct{}
+
+// Source names The StoryGraph
+func (StoryGraphImporter) Source() string { return "storygraph" }
+
+// storyGraphShelves maps StoryGraph read statuses to shelves
+var storyGraphShelves = map[string]string{
+	"read":              "read",
+	"to-read":           "to-read",
+	"currently-reading": "currently-reading",
+	"paused":            "currently-reading",
+	"did-not-finish":    books.ShelfDNF,
+}
+
+// Parse reads the CSV. "Dates Read" holds every read as start-finish
+// ranges, such as "2023/01/05-2023/01/20, 2021/03/01-2021/03/09".
+func (StoryGraphImporter) Parse(r io.Reader) ([]ImportedBook, error) {
+	rows, err := readTable(r, ',')
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ImportedBook, 0, len(rows))
+	for _, row := range rows {
+		authors := splitNames(row["Authors"])
+		b := Book{
+			Title:     row["Title"],
+			Format:    books.NormalizeFormat(row["Format"]),
+			DateAdded: importDate(row["Date Added"]),
+			Shelf:     storyGraphShelves[strings.ToLower(row["Read Status"])],
+			Review:    row["Review"],
+			Tags:      joinTags(row["Tags"]),
+			Rating:    importRating(row["Star Rating"]),
+		}
+		if b.Shelf == "" {
+			b.Shelf = "to-read"
+		}
+		if len(authors) > 0 {
+			b.Author = authors[0]
+		}
+		b.AdditionalAuthors = strings.Join(append(authors[min(1, len(authors)):], splitNames(row["Contributors"])...), ", ")
+		b.ISBN, b.ISBN13 = splitISBNs(row["ISBN/UID"])
+		if strings.EqualFold(row["Owned?"], "yes") {
+			b.Ownership = "owned"
+			b.OwnedFormat = b.Format
+		}
+
+		reads := storyGraphReads(row["Dates Read"], row["Last Date Read"])
+		if count, _ := strconv.Atoi(row["Read Count"]); b.Shelf == "read" {
+			// Reads StoryGraph counted without recording dates
+			for len(reads) < count {
+				reads = append([]Read{{}}, reads...)
+			}
+		}
+
+		ib := ImportedBook{Book: b, Reads: reads}
+		if n := len(reads); n > 0 {
+			latest := reads[n-1]
+			switch {
+			case b.Shelf == "read" && latest.DateFinished != "":
+				ib.Book.DateStarted, ib.Book.DateRead = latest.DateStarted, latest.DateFinished
+				ib.Reads = reads[:n-1]
+			case b.Shelf == books.ShelfDNF:
+				ib.Book.DateStarted, ib.Book.DNFDate = latest.DateStarted, latest.DateFinished
+				ib.Reads = reads[:n-1]
+			case b.Shelf == "currently-reading":
+				ib.Book.DateStarted = latest.DateStarted
+				ib.Reads = reads[:n-1]
+			}
+		}
+		result = append(result, ib)
+	}
+	return result, nil
+}
+
+// storyGraphReads parses "Dates Read", falling back to "Last Date Read".
+// A range with one date is taken as the finish date.
+func storyGraphReads(datesRead, lastDateRead string) []Read {
+	var reads []Read
+	for _, span := range strings.Split(datesRead, ",") {
+		start, finish, found := strings.Cut(strings.TrimSpace(span), "-")
+		if !found {
+			start, finish = "", start
+		}
+		rd := Read{DateStarted: importDate(start), DateFinished: importDate(finish)}
+		if rd.DateStarted != "" || rd.DateFinished != "" {
+			reads = append(reads, rd)
+		}
+	}
+	if len(reads) == 0 {
+		if d := importDate(lastDateRead); d != "" {
+			reads = append(reads, Read{DateFinished: d})
+		}
+	}
+	sortReads(reads)
+	return reads
+}
+
+// splitNames splits a comma-separated list of names
+func splitNames(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
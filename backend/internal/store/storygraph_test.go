@@ -0,0 +1,91 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+// storyGraphCSV is a trimmed StoryGraph export
+const storyGraphCSV = "\uFEFFTitle,Authors,Contributors,ISBN/UID,Format,Read Status,Date Added,Last Date Read,Dates Read,Read Count,Moods,Star Rating,Review,Tags,Owned?\n" +
+	"Kindred,Octavia E. Butler,,9780807083697,paperback,read,2023/01/02,2024/06/20,\"2021/03/01-2021/03/09, 2024/06/01-2024/06/20\",2,dark,3.75,\"Gripping, \"\"unsettling\"\".\",\"classics, Sci-Fi\",Yes\n" +
+	"The Three-Body Problem,Cixin Liu,Ken Liu (Translator),0765377063,audio,did-not-finish,2023/02/01,2023/04/10,2023/04/01-2023/04/10,0,,,,,No\n" +
+	"Piranesi,Susanna Clarke,,UID123,digital,to-read,2023/05/05,,,0,,,,,\n"
+
+// TestStoryGraphParse verifies statuses, reads, ratings, formats and tags
+func TestStoryGraphParse(t *testing.T) {
+	parsed, err := StoryGraphImporter{}.Parse(strings.NewReader(storyGraphCSV))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if len(parsed) != 3 {
+		t.Fatalf("Expected 3 books, got %d", len(parsed))
+	}
+
+	kindred := parsed[0]
+	b := kindred.Book
+	if b.Title != "Kindred" || b.ISBN13 != "9780807083697" || b.Shelf != "read" || b.Format != "print" {
+		t.Errorf("Unexpected book: %+v", b)
+	}
+	if b.Rating != 3.75 {
+		t.Errorf("Expected 3.75 stars kept in quarter stars, got %g", b.Rating)
+	}
+	if b.DateStarted != "2024/06/01" || b.DateRead != "2024/06/20" {
+		t.Errorf("Expected the latest read on the book, got %s-%s", b.DateStarted, b.DateRead)
+	}
+	if len(kindred.Reads) != 1 || kindred.Reads[0].DateFinished != "2021/03/09" {
+		t.Errorf("Expected the earlier read separately, got %+v", kindred.Reads)
+	}
+	if b.Tags != "classics, sci-fi" || b.Ownership != "owned" || b.Review != `Gripping, "unsettling".` {
+		t.Errorf("Unexpected tags, ownership or review: %+v", b)
+	}
+
+	dnf := parsed[1].Book
+	if dnf.Shelf != "dnf" || dnf.DNFDate != "2023/04/10" || dnf.DateRead != "" || dnf.Format != "audiobook" {
+		t.Errorf("Unexpected did-not-finish book: %+v", dnf)
+	}
+	if dnf.ISBN != "0765377063" || dnf.AdditionalAuthors != "Ken Liu (Translator)" {
+		t.Errorf("Unexpected ISBN or contributors: %+v", dnf)
+	}
+
+	tbr := parsed[2].Book
+	if tbr.Shelf != "to-read" || tbr.ISBN != "" || tbr.ISBN13 != "" || tbr.Format != "ebook" {
+		t.Errorf("Unexpected to-read book: %+v", tbr)
+	}
+}
+
+// TestImportBooksSkipsDuplicates verifies books already present by ISBN or
+// title and author are not imported again
+func TestImportBooksSkipsDuplicates(t *testing.T) {
+	s := setupTestStore(t)
+	defer s.Close()
+
+	if _, err := s.CreateBook(&Book{Title: "Piranesi", Author: "Susanna Clarke", Shelf: "read"}); err != nil {
+		t.Fatalf("Failed to create book: %v", err)
+	}
+
+	result, err := s.ImportBooks(StoryGraphImporter{}, strings.NewReader(storyGraphCSV))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Imported != 2 || result.Duplicates != 1 {
+		t.Errorf("Expected 2 imported and 1 duplicate, got %+v", result)
+	}
+
+	all, _ := s.GetAllBooks()
+	var kindredID int64
+	for _, b := range all {
+		if b.Title == "Kindred" {
+			kindredID = b.ID
+		}
+	}
+	reads, err := s.GetReads(kindredID)
+	if err != nil || len(reads) != 2 {
+		t.Errorf("Expected both reads of Kindred, got %+v (%v)", reads, err)
+	}
+
+	// Importing the same file again adds nothing
+	result, err = s.ImportBooks(StoryGraphImporter{}, strings.NewReader(storyGraphCSV))
+	if err != nil || result.Imported != 0 || result.Duplicates != 3 {
+		t.Errorf("Expected a re-import to find only duplicates, got %+v (%v)", result, err)
+	}
+}
@@ -94,6 +94,14 @@ func main() {
 	defer dataStore.Close()
 	handlers.SetStore(dataStore)
 	
+	// Run a subcommand such as import-clippings instead of serving
+	if len(os.Args) > 1 {
+		if err := runCommand(dataStore, os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	
 	// Check if we need to import from books.json
 	count, _ := dataStore.BookCount()
 	if count == 0 {
@@ -133,6 +141,37 @@ func main() {
 		}
 	})
 	http.HandleFunc("/api/books/", func(w http.ResponseWriter, r *http.Request) {
+		// Per-book reads: /api/books/:id/reads[/:readId]
+		if strings.Contains(r.URL.Path, "/reads") {
+			switch r.Method {
+			case http.MethodGet:
+				handlers.GetReads(w, r)
+			case http.MethodPost:
+				handlers.AuthMiddleware(handlers.AddRead)(w, r)
+			case http.MethodDelete:
+				handlers.AuthMiddleware(handlers.DeleteRead)(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+		// Lending: /api/books/:id/lend
+		if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/lend") {
+			handlers.AuthMiddleware(handlers.LendBook)(w, r)
+			return
+		}
+		// Per-book notes: /api/books/:id/notes
+		if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/notes") {
+			switch r.Method {
+			case http.MethodGet:
+				handlers.GetNotes(w, r)
+			case http.MethodPost:
+				handlers.AuthMiddleware(handlers.CreateNote)(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+			return
+		}
 		if r.Method == http.MethodPut {
 			handlers.AuthMiddleware(handlers.UpdateBook)(w, r)
 		} else if r.Method == http.MethodDelete {
@@ -141,7 +180,40 @@ func main() {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	})
+	http.HandleFunc("/api/notes/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			handlers.AuthMiddleware(handlers.UpdateNote)(w, r)
+		} else if r.Method == http.MethodDelete {
+			handlers.AuthMiddleware(handlers.DeleteNote)(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	http.HandleFunc("/api/search", handlers.Search)
+	http.HandleFunc("/api/loans", handlers.AuthMiddleware(handlers.GetLoans))
+	http.HandleFunc("/api/loans/", handlers.AuthMiddleware(handlers.ReturnLoan))
 	http.HandleFunc("/api/stats", handlers.GetStats)
+	http.HandleFunc("/api/stats/tbr", handlers.GetTBRStats)
+	http.HandleFunc("/api/stats/summary", handlers.GetStatsSummary)
+	http.HandleFunc("/api/review/", handlers.GetYearReview)
+	http.HandleFunc("/api/badge/", handlers.GetBadge)
+	http.HandleFunc("/api/calendar.ics", handlers.GetCalendar)
+	http.HandleFunc("/api/calendar/token", handlers.AuthMiddleware(handlers.GetCalendarToken))
+	http.HandleFunc("/opds", handlers.GetOPDS)
+	http.HandleFunc("/opds/", handlers.GetOPDS)
+	http.HandleFunc("/feed.atom", handlers.GetFeed)
+	http.HandleFunc("/feed.rss", handlers.GetFeed)
+	http.HandleFunc("/api/library/value", handlers.AuthMiddleware(handlers.GetLibraryValue))
+	http.HandleFunc("/api/tbr/pick", handlers.GetTBRPick)
+	http.HandleFunc("/api/series", handlers.GetSeries)
+	http.HandleFunc("/api/authors", handlers.GetAuthors)
+	http.HandleFunc("/api/authors/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/authors/merge" {
+			handlers.AuthMiddleware(handlers.MergeAuthors)(w, r)
+		} else {
+			handlers.GetAuthor(w, r)
+		}
+	})
 	
 	// Goals routes
 	http.HandleFunc("/api/goals/", handlers.GetGoal)
@@ -152,9 +224,17 @@ func main() {
 	http.HandleFunc("/api/auth/logout", handlers.Logout)
 	http.HandleFunc("/api/auth/check", handlers.CheckAuth)
 	
+	// Import routes (protected)
+	http.HandleFunc("/api/import/clippings", handlers.AuthMiddleware(handlers.ImportClippings))
+	http.HandleFunc("/api/import/", handlers.AuthMiddleware(handlers.ImportLibrary))
+	
 	// Export route (protected)
 	http.HandleFunc("/api/export", handlers.AuthMiddleware(handlers.ExportBooks))
 	
+	// Backup and restore routes (protected)
+	http.HandleFunc("/api/backup", handlers.AuthMiddleware(handlers.BackupDatabase))
+	http.HandleFunc("/api/restore", handlers.AuthMiddleware(handlers.RestoreDatabase))
+	
 	// Health check endpoint
 	http.HandleFunc("/api/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -190,7 +270,36 @@ func main() {
 	fmt.Println("  POST /api/books (auth required)")
 	fmt.Println("  PUT  /api/books/:id (auth required)")
 	fmt.Println("  DELETE /api/books/:id (auth required)")
+	fmt.Println("  GET  /api/books/:id/reads")
+	fmt.Println("  POST /api/books/:id/reads (auth required)")
+	fmt.Println("  GET  /api/books/:id/notes")
+	fmt.Println("  POST /api/books/:id/notes (auth required)")
+	fmt.Println("  PUT  /api/notes/:id (auth required)")
+	fmt.Println("  DELETE /api/notes/:id (auth required)")
+	fmt.Println("  GET  /api/search?q=...")
+	fmt.Println("  POST /api/books/:id/lend (auth required)")
+	fmt.Println("  GET  /api/loans?status=outstanding (auth required)")
+	fmt.Println("  POST /api/loans/:id/return (auth required)")
+	fmt.Println("  POST /api/import/clippings (auth required)")
+	fmt.Println("  POST /api/import/storygraph, /api/import/librarything, /api/import/calibre (auth required)")
+	fmt.Println("  GET  /api/export?format=csv|json|ndjson|bibtex|csl-json|markdown&shelf=&tag=&year= (auth required)")
+	fmt.Println("  GET  /api/backup (auth required)")
+	fmt.Println("  POST /api/restore (auth required)")
 	fmt.Println("  GET  /api/stats?year=2025")
+	fmt.Println("  GET  /api/stats/tbr")
+	fmt.Println("  GET  /api/stats/summary")
+	fmt.Println("  GET  /api/review/:year?format=html")
+	fmt.Println("  GET  /api/badge/:year.svg?theme=dark&size=medium&pages=true")
+	fmt.Println("  GET  /feed.atom, /feed.rss?shelf=fantasy")
+	fmt.Println("  GET  /opds (OPDS catalog)")
+	fmt.Println("  GET  /api/calendar.ics?token=...")
+	fmt.Println("  GET  /api/calendar/token (auth required)")
+	fmt.Println("  GET  /api/library/value (auth required)")
+	fmt.Println("  GET  /api/tbr/pick?count=5&seed=42")
+	fmt.Println("  GET  /api/series")
+	fmt.Println("  GET  /api/authors")
+	fmt.Println("  GET  /api/authors/:id")
+	fmt.Println("  POST /api/authors/merge (auth required)")
 	fmt.Println("  POST /api/auth/login")
 	fmt.Println("  GET  /admin (book entry form)")
 	